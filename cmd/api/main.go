@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"wallet-api/internal/delivery/http"
 	"wallet-api/internal/delivery/http/handler"
 	"wallet-api/internal/pkg/database"
+	"wallet-api/internal/service"
 
 	"github.com/joho/godotenv"
 )
@@ -36,7 +39,26 @@ func main() {
 	// 4. Setup Router with Handlers
 	app := http.SetupRouter(handlers)
 
-	// 5. Start Server
+	// 5. Release expired authorization holds in the background
+	sweepInterval, err := time.ParseDuration(os.Getenv("HOLD_SWEEP_INTERVAL"))
+	if err != nil || sweepInterval <= 0 {
+		sweepInterval = time.Minute
+	}
+	ctx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go service.StartHoldSweeper(ctx, handlers.WalletHandler.Service, sweepInterval)
+
+	// 6. Run due scheduled transfers in the background
+	scheduleInterval, err := time.ParseDuration(os.Getenv("SCHEDULE_WORKER_INTERVAL"))
+	if err != nil || scheduleInterval <= 0 {
+		scheduleInterval = time.Minute
+	}
+	go service.StartScheduleWorker(ctx, handlers.ScheduleHandler.Service, scheduleInterval)
+
+	// 7. Forget expired idempotency keys in the background
+	go service.StartIdempotencyKeySweeper(ctx, handlers.IdempotencyService, time.Hour)
+
+	// 8. Start Server
 	port := os.Getenv("APP_PORT")
 	if port == "" {
 		port = "3000" // Default port if not specified
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/database"
+	"wallet-api/internal/repository"
+	"wallet-api/internal/service"
+
+	"github.com/joho/godotenv"
+)
+
+// reconcile compares every wallets.balance with the balance replayed from the transactions
+// table and prints a drift report. With -fix it writes adjustment transactions so the history
+// adds up again; a wallet that cannot be fixed is reported with the reason and the rest are
+// still fixed. Meant to run nightly; it exits with status 2 when drift was found and left
+// unfixed so a scheduler can alert on it.
+func main() {
+	format := flag.String("format", "json", "report format: json or csv")
+	output := flag.String("output", "", "write the report to this file instead of stdout")
+	all := flag.Bool("all", false, "include wallets without drift in the report")
+	fix := flag.Bool("fix", false, "write adjustment transactions for every drifting wallet")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("Unknown format %q, use json or csv", *format)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	db := database.InitMySQL()
+	defer db.Close()
+
+	reconciler := service.NewReconciliationService(
+		db,
+		repository.NewMysqlWalletRepository(db),
+		repository.NewMysqlTransactionRepository(db),
+		repository.NewMysqlLedgerRepository(db),
+	)
+
+	ctx := context.Background()
+	drifts, err := reconciler.Report(ctx)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	report := make([]domain.BalanceDrift, 0, len(drifts))
+	drifting, unfixed := 0, 0
+	for _, drift := range drifts {
+		if !drift.HasDrift() {
+			if *all {
+				report = append(report, drift)
+			}
+			continue
+		}
+		drifting++
+
+		if !*fix {
+			unfixed++
+		} else if fixed, err := reconciler.Fix(ctx, drift.WalletID); err != nil {
+			log.Printf("Failed to fix wallet %d: %v", drift.WalletID, err)
+			message := err.Error()
+			drift.FixError = &message
+			unfixed++
+		} else {
+			drift = *fixed
+		}
+		report = append(report, drift)
+	}
+
+	if err := writeReport(*output, *format, report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	log.Printf("Checked %d wallets, %d with drift, %d left unfixed", len(drifts), drifting, unfixed)
+	if unfixed > 0 {
+		os.Exit(2)
+	}
+}
+
+// writeReport writes the report to path, or to stdout when path is empty
+func writeReport(path, format string, drifts []domain.BalanceDrift) error {
+	out := io.Writer(os.Stdout)
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if format == "csv" {
+		return writeCSV(out, drifts)
+	}
+	return writeJSON(out, drifts)
+}
+
+type reportRow struct {
+	domain.BalanceDrift
+	TransactionDrift domain.Money `json:"transaction_drift"`
+	LedgerDrift      domain.Money `json:"ledger_drift"`
+}
+
+func writeJSON(w io.Writer, drifts []domain.BalanceDrift) error {
+	rows := make([]reportRow, 0, len(drifts))
+	for _, drift := range drifts {
+		rows = append(rows, reportRow{
+			BalanceDrift:     drift,
+			TransactionDrift: drift.TransactionDrift(),
+			LedgerDrift:      drift.LedgerDrift(),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func writeCSV(w io.Writer, drifts []domain.BalanceDrift) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"wallet_id", "user_id", "system_code", "currency",
+		"stored_balance", "replayed_balance", "ledger_balance",
+		"transaction_drift", "ledger_drift", "adjustment_id", "fix_error",
+	}); err != nil {
+		return err
+	}
+
+	for _, drift := range drifts {
+		userID, systemCode, adjustmentID, fixError := "", "", "", ""
+		if drift.UserID != nil {
+			userID = strconv.FormatInt(*drift.UserID, 10)
+		}
+		if drift.SystemCode != nil {
+			systemCode = string(*drift.SystemCode)
+		}
+		if drift.AdjustmentID != nil {
+			adjustmentID = strconv.FormatInt(*drift.AdjustmentID, 10)
+		}
+		if drift.FixError != nil {
+			fixError = *drift.FixError
+		}
+		if err := writer.Write([]string{
+			strconv.FormatInt(drift.WalletID, 10),
+			userID,
+			systemCode,
+			drift.Currency,
+			drift.StoredBalance.String(),
+			drift.ReplayedBalance.String(),
+			drift.LedgerBalance.String(),
+			drift.TransactionDrift().String(),
+			drift.LedgerDrift().String(),
+			adjustmentID,
+			fixError,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -37,23 +39,32 @@ func main() {
 	}
 	fmt.Println("Connected to database successfully.")
 
-	// Read migration file
-	content, err := ioutil.ReadFile("migrations/20260211120000_create_initial_tables.up.sql")
+	// Read migration files in version order
+	files, err := filepath.Glob("migrations/*.up.sql")
 	if err != nil {
-		log.Fatalf("Failed to read migration file: %v", err)
+		log.Fatalf("Failed to list migration files: %v", err)
 	}
+	sort.Strings(files)
 
-	requests := strings.Split(string(content), ";")
-	for _, request := range requests {
-		request = strings.TrimSpace(request)
-		if request == "" {
-			continue
-		}
-		_, err := db.Exec(request)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
 		if err != nil {
-			log.Printf("Failed to execute statement: %s\nError: %v\n", request, err)
-		} else {
-			fmt.Println("Executed statement successfully.")
+			log.Fatalf("Failed to read migration file: %v", err)
+		}
+		fmt.Printf("Applying %s\n", file)
+
+		requests := strings.Split(string(content), ";")
+		for _, request := range requests {
+			request = strings.TrimSpace(request)
+			if request == "" {
+				continue
+			}
+			_, err := db.Exec(request)
+			if err != nil {
+				log.Printf("Failed to execute statement: %s\nError: %v\n", request, err)
+			} else {
+				fmt.Println("Executed statement successfully.")
+			}
 		}
 	}
 
@@ -15,44 +15,45 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login with email and password to receive JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/fees": {
+            "get": {
+                "description": "List every fee rule, grouped by operation and currency in tier order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Login user",
+                "summary": "List fee rules",
                 "parameters": [
                     {
-                        "description": "Login Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.ApiResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.FeeRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "401": {
@@ -68,11 +69,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user with name, email, and password",
+            },
+            "put": {
+                "description": "Create or replace the fee tier of an operation starting at min_amount. The fee is flat_fee plus percent_bps of the amount, kept between min_fee and max_fee.",
                 "consumes": [
                     "application/json"
                 ],
@@ -80,28 +79,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Set fee rule",
                 "parameters": [
                     {
-                        "description": "Register Request",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fee Rule",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterRequest"
+                            "$ref": "#/definitions/handler.SetFeeRuleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.FeeRule"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -110,14 +125,56 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/fees/{id}": {
+            "delete": {
+                "description": "Remove a fee tier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete fee rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "type": "integer",
+                        "description": "Fee Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
@@ -125,14 +182,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/history": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get transaction history for logged-in user with pagination",
+        "/admin/fx/rates": {
+            "put": {
+                "description": "Create or replace the mid rate and spread of a currency pair",
                 "consumes": [
                     "application/json"
                 ],
@@ -140,21 +192,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
                 ],
-                "summary": "Get transaction history",
+                "summary": "Set exchange rate",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Rate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetRateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -169,24 +225,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.Transaction"
-                                            }
+                                            "$ref": "#/definitions/domain.FXRate"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
@@ -194,32 +247,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/transfer": {
+        "/admin/fx/rates/import": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Transfer funds from logged-in user to another user",
+                "description": "Import rates from a CSV body with columns base_currency,quote_currency,rate[,spread_bps]",
                 "consumes": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
                 ],
-                "summary": "Transfer funds",
+                "summary": "Import exchange rates",
                 "parameters": [
                     {
-                        "description": "Transfer Request",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "CSV rates",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.TransferRequest"
+                            "type": "string"
                         }
                     }
                 ],
@@ -227,19 +282,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/utils.ApiResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/domain.Transaction"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
@@ -257,24 +303,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/profile": {
+        "/admin/limits": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get the profile of the logged-in user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the global limits (user_id 0) and every per-user override",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Admin"
+                ],
+                "summary": "List transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -287,7 +334,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.User"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.TransferLimit"
+                                            }
                                         }
                                     }
                                 }
@@ -300,12 +350,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/utils.ApiResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -313,16 +357,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/wallets/balance": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get current balance of logged-in user",
+            },
+            "put": {
+                "description": "Replace the limits applied to every user of a currency. Omitted fields are not capped.",
                 "consumes": [
                     "application/json"
                 ],
@@ -330,9 +367,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
+                ],
+                "summary": "Set global transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetLimitRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user balance",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -345,27 +400,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.Wallet"
+                                            "$ref": "#/definitions/domain.TransferLimit"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/utils.ApiResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
@@ -373,14 +422,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets/topup": {
+        "/admin/transactions/{id}/fail": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Top up wallet balance for logged-in user",
+                "description": "Mark a withdrawal whose payout is pending, or whose payout call failed with an unknown outcome, as failed and return the funds to the wallet",
                 "consumes": [
                     "application/json"
                 ],
@@ -388,17 +432,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
                 ],
-                "summary": "Top up wallet",
+                "summary": "Fail a pending withdrawal",
                 "parameters": [
                     {
-                        "description": "TopUp Request",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fail Withdrawal Request",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.TopUpRequest"
+                            "$ref": "#/definitions/handler.FailWithdrawalRequest"
                         }
                     }
                 ],
@@ -414,7 +471,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.Wallet"
+                                            "$ref": "#/definitions/domain.Transaction"
                                         }
                                     }
                                 }
@@ -433,62 +490,5072 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.ApiResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Withdrawal is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/{id}/reverse": {
+            "post": {
+                "description": "Refund a completed transfer in full or in part. The refund moves funds from the original receiver back to the sender.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reverse a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reverse Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReverseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction already fully reversed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/{id}/settle": {
+            "post": {
+                "description": "Mark a withdrawal whose payout is pending, or whose payout call failed with an unknown outcome, as paid out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Settle a pending withdrawal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settle Withdrawal Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SettleWithdrawalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Withdrawal is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/limits": {
+            "get": {
+                "description": "Show the limits applied to a user: the global limits merged with the user override",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get effective user limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "IDR",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.TransferLimit"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the override of a user. Omitted fields fall back to the global limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Override user transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.TransferLimit"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Drop the override of a user so the global limits apply again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Remove user transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "IDR",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallet-status-events": {
+            "get": {
+                "description": "Poll status changes of all wallets. Pass the id of the last event seen as after_id to get the next ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Wallet status feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Return events after this id",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.WalletStatusEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallets/{id}/statements": {
+            "get": {
+                "description": "Download the statement of any wallet, system wallets included, for compliance reviews",
+                "produces": [
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Download a monthly statement of any wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-03",
+                        "description": "Statement month (YYYY-MM)",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "csv or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallets/{id}/status": {
+            "put": {
+                "description": "Freeze, debit-freeze, reactivate or close a wallet. A debit-frozen wallet can still receive funds, a frozen wallet can neither send nor receive. Closing is final and needs an empty wallet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Change wallet status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetWalletStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is closed or still holds funds",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallets/{id}/status-events": {
+            "get": {
+                "description": "List every status change of a wallet, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List wallet status changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.WalletStatusEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login with email and password to receive JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with name, email, and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Register Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fees/quote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show the fee of a transfer or top-up before confirming it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fees"
+                ],
+                "summary": "Quote a fee",
+                "parameters": [
+                    {
+                        "description": "Fee Quote Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.FeeQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.FeeQuote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx/quotes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lock a conversion rate for a short time. Use the quote ID with /transactions/transfer/fx.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "FX"
+                ],
+                "summary": "Create FX quote",
+                "parameters": [
+                    {
+                        "description": "Quote Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.FXQuote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx/rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the locally stored mid rates and their spreads",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "FX"
+                ],
+                "summary": "List exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.FXRate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ask another user to pay an amount. The request expires after the configured TTL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "Request money",
+                "parameters": [
+                    {
+                        "description": "Payment Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreatePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.PaymentRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/incoming": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List payment requests the logged-in user has been asked to pay",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "List incoming payment requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, accepted, declined, cancelled or expired",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.PaymentRequest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/outgoing": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List payment requests the logged-in user has sent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "List outgoing payment requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, accepted, declined, cancelled or expired",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.PaymentRequest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a payment request the logged-in user sent or received",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "Get a payment request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment Request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.PaymentRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pay an incoming payment request with a transfer to the requester",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "Accept a payment request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment Request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.PaymentRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Request has expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw a payment request the logged-in user sent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "Cancel a payment request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment Request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.PaymentRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Request has expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Decline an incoming payment request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Requests"
+                ],
+                "summary": "Decline a payment request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment Request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.PaymentRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Request has expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/qr": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Decode a scanned QR payload, validate its CRC and transfer to the wallet the code is bound to. Dynamic codes carry their amount; static codes need one in the request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Pay a scanned QR code",
+                "parameters": [
+                    {
+                        "description": "QR Payment Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PayQRRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed payload or CRC mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Dynamic code already paid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Dynamic code has expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/qr/codes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the QR codes issued for the logged-in user's wallets, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "List QR codes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.QRCode"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue an EMVCo (QRIS-style) merchant-presented QR payload bound to one of the logged-in user's wallets. Without an amount the code is static and can be paid many times; with an amount it is dynamic, single-use and expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Generate a QR code",
+                "parameters": [
+                    {
+                        "description": "QR Code Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GenerateQRCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.QRCode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pay several recipients at once. Either every transfer is applied or none is. Each transfer is charged the transfer fee.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Batch transfer",
+                "parameters": [
+                    {
+                        "description": "Batch Transfer Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.BatchTransfer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Receiver not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED) or a receiver has no wallet in the currency",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream every transaction of the logged-in user that matches the filters, newest first, as CSV or as a JSON array. Takes the same filters as the history endpoint, without paging.",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Export transaction history",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "csv or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only wallets of this currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after (YYYY-MM-DD or RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created up to, a whole day when given as YYYY-MM-DD (YYYY-MM-DD or RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "in",
+                            "out"
+                        ],
+                        "type": "string",
+                        "description": "in or out",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Transaction status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "topup",
+                            "transfer",
+                            "withdrawal",
+                            "fee",
+                            "adjustment",
+                            "refund",
+                            "capture",
+                            "pocket"
+                        ],
+                        "type": "string",
+                        "description": "Transaction type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Other side by email, phone or @handle",
+                        "name": "counterparty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client reference attached by the sender",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Smallest amount, inclusive",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Largest amount, inclusive",
+                        "name": "max_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get transaction history for logged-in user, optionally filtered. Pages by page and limit, or by cursor when a cursor parameter is sent (empty for the newest page): data is then a TransactionPage with next_cursor and prev_cursor, and rows landing between two requests never shift the pages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get transaction history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of an earlier page; switches to cursor paging",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only wallets of this currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after (YYYY-MM-DD or RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created up to, a whole day when given as YYYY-MM-DD (YYYY-MM-DD or RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "in",
+                            "out"
+                        ],
+                        "type": "string",
+                        "description": "in or out",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Transaction status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "topup",
+                            "transfer",
+                            "withdrawal",
+                            "fee",
+                            "adjustment",
+                            "refund",
+                            "capture",
+                            "pocket"
+                        ],
+                        "type": "string",
+                        "description": "Transaction type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Other side by email, phone or @handle",
+                        "name": "counterparty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client reference attached by the sender",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Smallest amount, inclusive",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Largest amount, inclusive",
+                        "name": "max_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page mode; cursor mode returns data=domain.TransactionPage without meta",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Transaction"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/database.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/holds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserve funds for a merchant. Held funds stay in the balance but cannot be spent until the hold is captured, voided or expires. An active hold counts towards the transfer limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Authorize funds for a merchant",
+                "parameters": [
+                    {
+                        "description": "Hold Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.HoldRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Hold"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED) or merchant has no wallet in the currency",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/holds/{id}/capture": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Capture all or part of a hold placed for the logged-in merchant. The rest of the hold is released.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Capture a hold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Capture Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CaptureRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold already settled or expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/holds/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Release a hold placed for the logged-in merchant without moving any money",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Void a hold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Hold"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold already settled or expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the scheduled transfers of the logged-in user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "List scheduled transfers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.ScheduledTransfer"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule a one-off or recurring transfer. Monthly schedules run on the day of month of start_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Schedule a transfer",
+                "parameters": [
+                    {
+                        "description": "Schedule Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.ScheduledTransfer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a scheduled transfer with its latest executions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Get a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ScheduleDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the amount, timing or status of a scheduled transfer. Omitted fields keep their value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Update a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Schedule Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.ScheduledTransfer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Schedule is completed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop a scheduled transfer for good. Its execution history is kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Cancel a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Schedule is completed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer funds from logged-in user to another user. A transfer fee, if any, is charged to the sender on top of the amount; see /fees/quote. A description, a client reference and metadata can be attached and are returned in history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Transfer funds",
+                "parameters": [
+                    {
+                        "description": "Transfer Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pocket or receiver not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "client_reference already used by the sender",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED) or currency mismatch between sender and receiver",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/transfer/fx": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer funds in another currency using a quote from /fx/quotes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Cross-currency transfer",
+                "parameters": [
+                    {
+                        "description": "FX Transfer Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.FXTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED) or receiver has no wallet in the target currency",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw funds to an external bank account. The funds are held while the payout is pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Withdraw funds",
+                "parameters": [
+                    {
+                        "description": "Withdraw Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WithdrawRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Transfer limit exceeded (LIMIT_EXCEEDED)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.LimitExceededError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find who an email, phone number or @handle belongs to before sending money. Only a masked name is returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Look up a payee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "@budi",
+                        "description": "Email, phone number or @handle",
+                        "name": "recipient",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Payee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the profile of the logged-in user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile/contact": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set or remove the phone number and public handle other users can send money to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update contact details",
+                "parameters": [
+                    {
+                        "description": "Contact details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateContactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone number or handle already used by another user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get current balance of every open pocket of logged-in user, primary wallets first, or of a single pocket",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get user balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only this pocket",
+                        "name": "wallet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Wallet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/pockets": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Open a named wallet, e.g. \"Savings\". The first wallet of a currency becomes its primary wallet and receives transfers from other users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pockets"
+                ],
+                "summary": "Create a pocket",
+                "parameters": [
+                    {
+                        "description": "Pocket",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreatePocketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name already used by another pocket",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/pockets/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move money between two pockets of the logged-in user in the same currency. No fee is charged and transfer limits do not apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pockets"
+                ],
+                "summary": "Move money between pockets",
+                "parameters": [
+                    {
+                        "description": "Move Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MovePocketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Transaction"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Pocket is in another currency",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.CurrencyMismatchError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/pockets/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the name of an open pocket of the logged-in user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pockets"
+                ],
+                "summary": "Rename a pocket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RenamePocketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Pocket is archived or the name is already used",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/pockets/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close an empty pocket. Archived pockets are hidden from the balance and cannot take money again. The primary wallet of a currency cannot be archived.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pockets"
+                ],
+                "summary": "Archive a pocket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Pocket is primary, already archived or still holds funds",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/statements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the statement of one of the logged-in user's wallets for a month: opening balance, every transaction with the running balance and closing balance. Defaults to the primary IDR wallet and CSV.",
+                "produces": [
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Download a monthly statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-03",
+                        "description": "Statement month (YYYY-MM)",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pocket to report on",
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Primary wallet of this currency when wallet_id is not set",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "csv or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/topup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Top up wallet balance for logged-in user. A top-up fee, if any, is taken out of the credited amount; see /fees/quote.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Top up wallet",
+                "parameters": [
+                    {
+                        "description": "TopUp Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TopUpRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is frozen, closed or archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pocket not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Pocket is in another currency",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/domain.CurrencyMismatchError"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ApiResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "database.Meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_next": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.BatchTransfer": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "total_fee": {
+                    "description": "Transfer fees charged on top of Total",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Transaction"
+                    }
+                }
+            }
+        },
+        "domain.CurrencyMismatchError": {
+            "type": "object",
+            "properties": {
+                "source_currency": {
+                    "type": "string"
+                },
+                "target_currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FXQuote": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mid_rate": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate applied after the spread",
+                    "type": "string"
+                },
+                "source_amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "source_currency": {
+                    "type": "string"
+                },
+                "spread": {
+                    "description": "In the target currency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "spread_bps": {
+                    "type": "integer"
+                },
+                "target_amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "target_currency": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FXRate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "quote_currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "rate": {
+                    "description": "Units of quote currency per unit of base currency",
+                    "type": "string",
+                    "example": "15650.00000000"
+                },
+                "spread_bps": {
+                    "description": "Basis points taken off the mid rate",
+                    "type": "integer",
+                    "example": 50
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FeeOperation": {
+            "type": "string",
+            "enum": [
+                "transfer",
+                "topup"
+            ],
+            "x-enum-varnames": [
+                "FeeOperationTransfer",
+                "FeeOperationTopUp"
+            ]
+        },
+        "domain.FeeQuote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "fee": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "operation": {
+                    "$ref": "#/definitions/domain.FeeOperation"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Debited for a transfer, credited for a top-up",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.FeeRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "flat_fee": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_fee": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "min_amount": {
+                    "description": "Lower bound of the tier",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "min_fee": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "operation": {
+                    "$ref": "#/definitions/domain.FeeOperation"
+                },
+                "percent_bps": {
+                    "description": "Basis points of the amount, added to FlatFee",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Hold": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "captured_amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merchant_user_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.HoldStatus"
+                },
+                "transaction_id": {
+                    "description": "Capture transaction",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.HoldStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "captured",
+                "voided",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "HoldStatusActive",
+                "HoldStatusCaptured",
+                "HoldStatusVoided",
+                "HoldStatusExpired"
+            ]
+        },
+        "domain.LimitExceededError": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "max": {
+                    "type": "string"
+                },
+                "used": {
+                    "description": "Already used in the period, empty for the per-transaction limit",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "encoded as a decimal string, see MarshalJSON",
+                    "type": "string",
+                    "example": "15000.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                }
+            }
+        },
+        "domain.Payee": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "description": "Masked name",
+                    "type": "string",
+                    "example": "B*** S******"
+                },
+                "handle": {
+                    "type": "string",
+                    "example": "budi"
+                }
+            }
+        },
+        "domain.PaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payer_user_id": {
+                    "type": "integer"
+                },
+                "requester_user_id": {
+                    "type": "integer"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.PaymentRequestStatus"
+                },
+                "transaction_id": {
+                    "description": "Transfer made on accept",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PaymentRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "accepted",
+                "declined",
+                "cancelled",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "PaymentRequestStatusCancelled": "Withdrawn by the requester"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "Withdrawn by the requester",
+                ""
+            ],
+            "x-enum-varnames": [
+                "PaymentRequestStatusPending",
+                "PaymentRequestStatusAccepted",
+                "PaymentRequestStatusDeclined",
+                "PaymentRequestStatusCancelled",
+                "PaymentRequestStatusExpired"
+            ]
+        },
+        "domain.QRCode": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Dynamic codes only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Dynamic codes only",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merchant_city": {
+                    "type": "string"
+                },
+                "merchant_name": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "EMVCo string to render as a QR image",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Bill number shown to the payer",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.QRCodeStatus"
+                },
+                "transaction_id": {
+                    "description": "Payment of a dynamic code",
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.QRCodeType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.QRCodeStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paid",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "QRCodeStatusExpired": "Dynamic codes only, derived from ExpiresAt",
+                "QRCodeStatusPaid": "Dynamic codes only"
+            },
+            "x-enum-descriptions": [
+                "",
+                "Dynamic codes only",
+                "Dynamic codes only, derived from ExpiresAt"
+            ],
+            "x-enum-varnames": [
+                "QRCodeStatusActive",
+                "QRCodeStatusPaid",
+                "QRCodeStatusExpired"
+            ]
+        },
+        "domain.QRCodeType": {
+            "type": "string",
+            "enum": [
+                "static",
+                "dynamic"
+            ],
+            "x-enum-comments": {
+                "QRCodeTypeDynamic": "One payment of a fixed amount before it expires",
+                "QRCodeTypeStatic": "Reusable, the payer enters the amount"
+            },
+            "x-enum-descriptions": [
+                "Reusable, the payer enters the amount",
+                "One payment of a fixed amount before it expires"
+            ],
+            "x-enum-varnames": [
+                "QRCodeTypeStatic",
+                "QRCodeTypeDynamic"
+            ]
+        },
+        "domain.ScheduleExecution": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "executed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "occurrence_at": {
+                    "description": "When the occurrence was due",
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.TransactionStatus"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ScheduleFrequency": {
+            "type": "string",
+            "enum": [
+                "once",
+                "daily",
+                "weekly",
+                "monthly"
+            ],
+            "x-enum-comments": {
+                "ScheduleFrequencyMonthly": "Same day of month as StartAt, clamped to the month end"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "Same day of month as StartAt, clamped to the month end"
+            ],
+            "x-enum-varnames": [
+                "ScheduleFrequencyOnce",
+                "ScheduleFrequencyDaily",
+                "ScheduleFrequencyWeekly",
+                "ScheduleFrequencyMonthly"
+            ]
+        },
+        "domain.ScheduleStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paused",
+                "completed",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "ScheduleStatusPaused": "By the user or after repeated failures"
+            },
+            "x-enum-descriptions": [
+                "",
+                "By the user or after repeated failures",
+                "",
+                ""
+            ],
+            "x-enum-varnames": [
+                "ScheduleStatusActive",
+                "ScheduleStatusPaused",
+                "ScheduleStatusCompleted",
+                "ScheduleStatusCancelled"
+            ]
+        },
+        "domain.ScheduledTransfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "attempts": {
+                    "description": "Failed attempts of the current occurrence",
+                    "type": "integer"
+                },
+                "consecutive_failures": {
+                    "description": "Occurrences failed in a row",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "$ref": "#/definitions/domain.ScheduleFrequency"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "occurrences": {
+                    "description": "Occurrences already run or skipped",
+                    "type": "integer"
+                },
+                "receiver_user_id": {
+                    "type": "integer"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ScheduleStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SystemWallet": {
+            "type": "string",
+            "enum": [
+                "treasury",
+                "topup_clearing",
+                "fees",
+                "suspense",
+                "fx_position"
+            ],
+            "x-enum-comments": {
+                "SystemWalletFXPosition": "Buys and sells currencies on cross-currency transfers",
+                "SystemWalletFees": "Collects fees",
+                "SystemWalletSuspense": "Counterpart of reconciliation adjustments",
+                "SystemWalletTopUpClearing": "Counterpart of top-ups",
+                "SystemWalletTreasury": "Counterpart of payouts to banks"
+            },
+            "x-enum-descriptions": [
+                "Counterpart of payouts to banks",
+                "Counterpart of top-ups",
+                "Collects fees",
+                "Counterpart of reconciliation adjustments",
+                "Buys and sells currencies on cross-currency transfers"
+            ],
+            "x-enum-varnames": [
+                "SystemWalletTreasury",
+                "SystemWalletTopUpClearing",
+                "SystemWalletFees",
+                "SystemWalletSuspense",
+                "SystemWalletFXPosition"
+            ]
+        },
+        "domain.Transaction": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "bank_account_name": {
+                    "description": "Withdrawals only",
+                    "type": "string"
+                },
+                "bank_account_number": {
+                    "description": "Withdrawals only",
+                    "type": "string"
+                },
+                "bank_code": {
+                    "description": "Withdrawals only",
+                    "type": "string"
+                },
+                "batch_id": {
+                    "description": "Shared by every leg of a batch transfer",
+                    "type": "string"
+                },
+                "client_reference": {
+                    "description": "Sender's own reference, unique per sending user",
+                    "type": "string"
+                },
+                "counter_amount": {
+                    "description": "Amount credited on cross-currency transfers",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "counter_currency": {
+                    "description": "Currency of CounterAmount",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "Relative to the wallet history is read for",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TransactionDirection"
+                        }
+                    ]
+                },
+                "exchange_for_id": {
+                    "description": "Cross-currency transfer an exchange settles",
+                    "type": "integer"
+                },
+                "external_reference": {
+                    "description": "Payout provider reference",
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "fee": {
+                    "description": "Fee charged with the transaction, set when it is created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "fee_for_id": {
+                    "description": "Transaction a fee was charged for",
+                    "type": "integer"
+                },
+                "fx_rate": {
+                    "type": "string"
+                },
+                "fx_spread": {
+                    "description": "Spread kept by the platform, in the counter currency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object"
+                },
+                "receiver_wallet_id": {
+                    "description": "Nil only on withdrawals recorded before system wallets existed",
+                    "type": "integer"
+                },
+                "reversal_of_id": {
+                    "description": "Original transaction of a refund",
+                    "type": "integer"
+                },
+                "reversal_reason": {
+                    "type": "string"
+                },
+                "sender_wallet_id": {
+                    "description": "Nil only on top-ups recorded before system wallets existed",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.TransactionStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.TransactionType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TransactionDirection": {
+            "type": "string",
+            "enum": [
+                "in",
+                "out"
+            ],
+            "x-enum-varnames": [
+                "TransactionDirectionIn",
+                "TransactionDirectionOut"
+            ]
+        },
+        "domain.TransactionStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "success",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "TransactionStatusPending",
+                "TransactionStatusSuccess",
+                "TransactionStatusFailed"
+            ]
+        },
+        "domain.TransactionType": {
+            "type": "string",
+            "enum": [
+                "topup",
+                "transfer",
+                "withdrawal",
+                "fee",
+                "adjustment",
+                "refund",
+                "capture",
+                "pocket",
+                "exchange"
+            ],
+            "x-enum-comments": {
+                "TransactionTypeCapture": "Captured hold",
+                "TransactionTypeExchange": "FX position wallets settling a cross-currency transfer",
+                "TransactionTypePocket": "Move between two pockets of one user"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "Captured hold",
+                "Move between two pockets of one user",
+                "FX position wallets settling a cross-currency transfer"
+            ],
+            "x-enum-varnames": [
+                "TransactionTypeTopUp",
+                "TransactionTypeTransfer",
+                "TransactionTypeWithdrawal",
+                "TransactionTypeFee",
+                "TransactionTypeAdjustment",
+                "TransactionTypeRefund",
+                "TransactionTypeCapture",
+                "TransactionTypePocket",
+                "TransactionTypeExchange"
+            ]
+        },
+        "domain.TransferLimit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "daily_amount_max": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "daily_count_max": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "monthly_amount_max": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "monthly_count_max": {
+                    "type": "integer"
+                },
+                "per_transaction_max": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "0 for the global limits",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.User": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "handle": {
+                    "description": "Public handle without the @",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone": {
+                    "description": "E.164, e.g. +6281234567890",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Wallet": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "Archived pockets are empty and take no more money",
+                    "type": "string"
+                },
+                "available_balance": {
+                    "description": "Balance minus HeldBalance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "balance": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO-4217",
+                    "type": "string"
+                },
+                "held_balance": {
+                    "description": "Reserved by active holds, still part of Balance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Money"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_primary": {
+                    "description": "One primary wallet per user per currency receives transfers",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Pocket name chosen by the user",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.WalletStatus"
+                },
+                "status_changed_at": {
+                    "type": "string"
+                },
+                "status_changed_by": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                },
+                "system_code": {
+                    "description": "Set for system wallets only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.SystemWallet"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Nil for system wallets",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WalletStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "debit_frozen",
+                "frozen",
+                "closed"
+            ],
+            "x-enum-comments": {
+                "WalletStatusClosed": "Final, the wallet must be empty",
+                "WalletStatusDebitFrozen": "Can receive but not send",
+                "WalletStatusFrozen": "Can neither send nor receive"
+            },
+            "x-enum-descriptions": [
+                "",
+                "Can receive but not send",
+                "Can neither send nor receive",
+                "Final, the wallet must be empty"
+            ],
+            "x-enum-varnames": [
+                "WalletStatusActive",
+                "WalletStatusDebitFrozen",
+                "WalletStatusFrozen",
+                "WalletStatusClosed"
+            ]
+        },
+        "domain.WalletStatusEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/domain.WalletStatus"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/domain.WalletStatus"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.BatchTransferItem": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "25000.00"
+                },
+                "receiver": {
+                    "description": "Email, phone number or @handle of the receiver",
+                    "type": "string",
+                    "example": "budi@example.com"
+                },
+                "receiver_user_id": {
+                    "description": "Deprecated, use receiver",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.BatchTransferRequest": {
+            "type": "object",
+            "required": [
+                "transfers"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.BatchTransferItem"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Transaction": {
+        },
+        "handler.CaptureRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Leave empty to capture the full hold",
+                    "type": "string",
+                    "example": "120000.00"
+                }
+            }
+        },
+        "handler.CreatePaymentRequestRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payer_user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "75000.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Dinner on Friday"
+                },
+                "payer_user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.CreatePocketRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Savings"
+                }
+            }
+        },
+        "handler.CreateScheduleRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "frequency",
+                "receiver_user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "500000.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ScheduleFrequency"
+                        }
+                    ],
+                    "example": "monthly"
+                },
+                "receiver_user_id": {
+                    "type": "integer"
+                },
+                "start_at": {
+                    "description": "Defaults to now",
+                    "type": "string",
+                    "example": "2026-04-01T09:00:00+07:00"
+                }
+            }
+        },
+        "handler.FXTransferRequest": {
+            "type": "object",
+            "required": [
+                "quote_id",
+                "receiver_user_id"
+            ],
+            "properties": {
+                "quote_id": {
+                    "type": "string"
+                },
+                "receiver_user_id": {
+                    "description": "May be the sender to convert between own wallets",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.FailWithdrawalRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Account closed"
+                }
+            }
+        },
+        "handler.FeeQuoteRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "operation"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100000.00"
+                },
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "operation": {
+                    "description": "transfer or topup",
+                    "type": "string",
+                    "example": "transfer"
+                }
+            }
+        },
+        "handler.GenerateQRCodeRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Set for a dynamic, single-payment code",
+                    "type": "string",
+                    "example": "45000.00"
+                },
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "merchant_city": {
+                    "description": "At most 15 characters",
+                    "type": "string",
+                    "example": "Jakarta"
+                },
+                "merchant_name": {
+                    "description": "Defaults to the user name, at most 25 characters",
+                    "type": "string",
+                    "example": "Warung Budi"
+                },
+                "reference": {
+                    "description": "Bill number shown to the payer",
+                    "type": "string",
+                    "example": "INV-2026-0042"
+                },
+                "wallet_id": {
+                    "description": "Defaults to the primary wallet of the currency",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handler.HoldRequest": {
             "type": "object",
             "required": [
-                "amount"
+                "amount",
+                "merchant_user_id"
             ],
             "properties": {
                 "amount": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "150000.00"
                 },
-                "created_at": {
-                    "type": "string"
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
                 },
-                "id": {
-                    "type": "integer"
+                "expires_in_seconds": {
+                    "description": "Defaults to 7 days, at most 30 days",
+                    "type": "integer",
+                    "example": 3600
                 },
-                "receiver_wallet_id": {
-                    "description": "Nullable if withdrawing to external",
+                "merchant_user_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
                 },
-                "sender_wallet_id": {
-                    "description": "Nullable if system sends money",
-                    "type": "integer"
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MovePocketRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_wallet_id",
+                "to_wallet_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "250000.00"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.TransactionStatus"
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
                 },
-                "updated_at": {
-                    "type": "string"
+                "from_wallet_id": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "to_wallet_id": {
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
-        "domain.TransactionStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "success",
-                "failed"
+        "handler.PayQRRequest": {
+            "type": "object",
+            "required": [
+                "payload"
             ],
-            "x-enum-varnames": [
-                "TransactionStatusPending",
-                "TransactionStatusSuccess",
-                "TransactionStatusFailed"
-            ]
+            "properties": {
+                "amount": {
+                    "description": "Required for static codes",
+                    "type": "string",
+                    "example": "45000.00"
+                },
+                "payload": {
+                    "description": "Scanned QR string",
+                    "type": "string",
+                    "example": "00020101021226500014ID.TLAB.WALLET...6304ABCD"
+                }
+            }
         },
-        "domain.User": {
+        "handler.QuoteRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "source_currency",
+                "target_currency"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1000000.00"
+                },
+                "source_currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "target_currency": {
+                    "type": "string",
+                    "example": "USD"
+                }
+            }
+        },
+        "handler.RegisterRequest": {
             "type": "object",
             "required": [
                 "email",
@@ -496,14 +5563,13 @@ const docTemplate = `{
                 "password"
             ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
                 "email": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "handle": {
+                    "description": "Optional public handle, lets others pay @budi",
+                    "type": "string",
+                    "example": "budi"
                 },
                 "name": {
                     "type": "string"
@@ -512,66 +5578,185 @@ const docTemplate = `{
                     "type": "string",
                     "minLength": 6
                 },
-                "updated_at": {
-                    "type": "string"
+                "phone": {
+                    "description": "Optional, lets others pay by phone number",
+                    "type": "string",
+                    "example": "081234567890"
                 }
             }
         },
-        "domain.Wallet": {
+        "handler.RenamePocketRequest": {
             "type": "object",
             "required": [
-                "user_id"
+                "name"
             ],
             "properties": {
-                "balance": {
-                    "type": "number"
+                "name": {
+                    "type": "string",
+                    "example": "Holiday fund"
+                }
+            }
+        },
+        "handler.ReverseRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Leave empty to reverse what is left",
+                    "type": "string",
+                    "example": "5000.00"
                 },
-                "created_at": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "example": "Duplicate payment"
+                }
+            }
+        },
+        "handler.ScheduleDetail": {
+            "type": "object",
+            "properties": {
+                "executions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ScheduleExecution"
+                    }
                 },
-                "id": {
-                    "type": "integer"
+                "schedule": {
+                    "$ref": "#/definitions/domain.ScheduledTransfer"
+                }
+            }
+        },
+        "handler.SetFeeRuleRequest": {
+            "type": "object",
+            "required": [
+                "operation"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
                 },
-                "updated_at": {
-                    "type": "string"
+                "flat_fee": {
+                    "type": "string",
+                    "example": "2500.00"
                 },
-                "user_id": {
-                    "type": "integer"
+                "max_fee": {
+                    "type": "string",
+                    "example": "25000.00"
+                },
+                "min_amount": {
+                    "description": "Lower bound of the tier",
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "min_fee": {
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "operation": {
+                    "description": "transfer or topup",
+                    "type": "string",
+                    "example": "transfer"
+                },
+                "percent_bps": {
+                    "type": "integer",
+                    "example": 50
                 }
             }
         },
-        "handler.LoginRequest": {
+        "handler.SetLimitRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "daily_amount_max": {
+                    "type": "string",
+                    "example": "20000000.00"
+                },
+                "daily_count_max": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "monthly_amount_max": {
+                    "type": "string",
+                    "example": "100000000.00"
+                },
+                "monthly_count_max": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "per_transaction_max": {
+                    "type": "string",
+                    "example": "10000000.00"
+                }
+            }
+        },
+        "handler.SetRateRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "base_currency",
+                "quote_currency",
+                "rate"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "base_currency": {
+                    "type": "string",
+                    "example": "USD"
                 },
-                "password": {
-                    "type": "string"
+                "quote_currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "15650.00"
+                },
+                "spread_bps": {
+                    "type": "integer",
+                    "example": 50
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "handler.SetWalletStatusRequest": {
             "type": "object",
             "required": [
-                "email",
-                "name",
-                "password"
+                "actor",
+                "reason",
+                "status"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "actor": {
+                    "type": "string",
+                    "example": "ops@tlab.id"
                 },
-                "name": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "example": "Account takeover reported by user"
                 },
-                "password": {
+                "status": {
+                    "description": "active, debit_frozen, frozen or closed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WalletStatus"
+                        }
+                    ],
+                    "example": "frozen"
+                }
+            }
+        },
+        "handler.SettleWithdrawalRequest": {
+            "type": "object",
+            "properties": {
+                "reference": {
+                    "description": "Payout provider reference",
                     "type": "string",
-                    "minLength": 6
+                    "example": "BCA-20260316-000123"
                 }
             }
         },
@@ -583,22 +5768,147 @@ const docTemplate = `{
             ],
             "properties": {
                 "amount": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "50000.00"
+                },
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "wallet_id": {
+                    "description": "Pocket to credit, defaults to the primary wallet of the currency",
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
         "handler.TransferRequest": {
             "type": "object",
             "required": [
-                "amount",
-                "receiver_user_id"
+                "amount"
             ],
             "properties": {
                 "amount": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "15000.00"
+                },
+                "client_reference": {
+                    "description": "Your own reference, unique across your wallets",
+                    "type": "string",
+                    "example": "ORDER-2026-0042"
+                },
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "description": {
+                    "description": "Shown to both sides, at most 255 characters",
+                    "type": "string",
+                    "example": "Dinner at Sate Khas Senayan"
+                },
+                "metadata": {
+                    "description": "Free-form JSON object, at most 4 KB",
+                    "type": "object"
+                },
+                "receiver": {
+                    "description": "Email, phone number or @handle of the receiver",
+                    "type": "string",
+                    "example": "@budi"
                 },
                 "receiver_user_id": {
+                    "description": "Deprecated, use receiver",
                     "type": "integer"
+                },
+                "wallet_id": {
+                    "description": "Pocket to pay from, defaults to the primary wallet of the currency",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handler.UpdateContactRequest": {
+            "type": "object",
+            "properties": {
+                "handle": {
+                    "description": "Omit to keep, empty to remove",
+                    "type": "string",
+                    "example": "budi"
+                },
+                "phone": {
+                    "description": "Omit to keep, empty to remove",
+                    "type": "string",
+                    "example": "081234567890"
+                }
+            }
+        },
+        "handler.UpdateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "750000.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ScheduleFrequency"
+                        }
+                    ],
+                    "example": "monthly"
+                },
+                "start_at": {
+                    "description": "Restarts the schedule from this time",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active or paused",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ScheduleStatus"
+                        }
+                    ],
+                    "example": "paused"
+                }
+            }
+        },
+        "handler.WithdrawRequest": {
+            "type": "object",
+            "required": [
+                "account_name",
+                "account_number",
+                "amount",
+                "bank_code"
+            ],
+            "properties": {
+                "account_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "account_number": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "100000.00"
+                },
+                "bank_code": {
+                    "type": "string",
+                    "example": "BCA"
+                },
+                "currency": {
+                    "description": "Defaults to IDR",
+                    "type": "string",
+                    "example": "IDR"
                 }
             }
         },
@@ -613,6 +5923,14 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
+                "meta": {
+                    "description": "Set on paged lists",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.Meta"
+                        }
+                    ]
+                },
                 "success": {
                     "type": "boolean"
                 }
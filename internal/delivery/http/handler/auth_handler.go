@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"wallet-api/internal/domain"
 	"wallet-api/internal/pkg/utils"
 
@@ -19,6 +20,13 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	Phone    string `json:"phone,omitempty" example:"081234567890"` // Optional, lets others pay by phone number
+	Handle   string `json:"handle,omitempty" example:"budi"`        // Optional public handle, lets others pay @budi
+}
+
+type UpdateContactRequest struct {
+	Phone  *string `json:"phone,omitempty" example:"081234567890"` // Omit to keep, empty to remove
+	Handle *string `json:"handle,omitempty" example:"budi"`        // Omit to keep, empty to remove
 }
 
 type LoginRequest struct {
@@ -49,14 +57,19 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	user := domain.User{
 		Name:     req.Name,
 		Email:    req.Email,
+		Phone:    optionalString(req.Phone),
+		Handle:   optionalString(req.Handle),
 		Password: req.Password,
 	}
 
 	if err := h.Service.Register(c.Context(), &user); err != nil {
 		// Differentiate error types (e.g. email exists vs internal server error)
-		if err.Error() == "email already registered" {
+		if err.Error() == "email already registered" || errors.Is(err, domain.ErrPhoneTaken) || errors.Is(err, domain.ErrHandleTaken) {
 			return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
 		}
+		if errors.Is(err, domain.ErrInvalidPhone) || errors.Is(err, domain.ErrInvalidHandle) {
+			return utils.BadRequest(c, err.Error(), nil)
+		}
 		// Log the actual error for internal server errors via utils helper
 		return utils.InternalServerError(c, "Failed to register user", err.Error())
 	}
@@ -131,3 +144,78 @@ func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 
 	return utils.Success(c, fiber.StatusOK, "User profile retrieved", user)
 }
+
+// UpdateContact godoc
+// @Summary Update contact details
+// @Description Set or remove the phone number and public handle other users can send money to
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateContactRequest true "Contact details"
+// @Success 200 {object} utils.ApiResponse{data=domain.User}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Phone number or handle already used by another user"
+// @Router /users/profile/contact [put]
+func (h *AuthHandler) UpdateContact(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req UpdateContactRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequest(c, "Invalid request body", err.Error())
+	}
+
+	user, err := h.Service.UpdateContact(c.Context(), userID, req.Phone, req.Handle)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPhoneTaken), errors.Is(err, domain.ErrHandleTaken):
+			return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+		case errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrInvalidHandle):
+			return utils.BadRequest(c, err.Error(), nil)
+		}
+		return utils.InternalServerError(c, "Failed to update contact details", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Contact details updated", user)
+}
+
+// LookupPayee godoc
+// @Summary Look up a payee
+// @Description Find who an email, phone number or @handle belongs to before sending money. Only a masked name is returned.
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param recipient query string true "Email, phone number or @handle" example(@budi)
+// @Success 200 {object} utils.ApiResponse{data=domain.Payee}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Router /users/lookup [get]
+func (h *AuthHandler) LookupPayee(c *fiber.Ctx) error {
+	payee, err := h.Service.LookupPayee(c.Context(), c.Query("recipient"))
+	if err != nil {
+		return recipientError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Payee found", payee)
+}
+
+// recipientError maps recipient lookup errors to their status codes
+func recipientError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrRecipientNotFound) {
+		return utils.NotFound(c, err.Error())
+	}
+	return utils.BadRequest(c, err.Error(), nil)
+}
+
+// optionalString turns an empty request field into nil
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strings"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeeHandler struct {
+	Service domain.FeeService
+}
+
+func NewFeeHandler(s domain.FeeService) *FeeHandler {
+	return &FeeHandler{Service: s}
+}
+
+type FeeQuoteRequest struct {
+	Operation string       `json:"operation" example:"transfer" validate:"required"` // transfer or topup
+	Amount    domain.Money `json:"amount" swaggertype:"string" example:"100000.00" validate:"required"`
+	Currency  string       `json:"currency" example:"IDR"` // Defaults to IDR
+}
+
+type SetFeeRuleRequest struct {
+	Operation  string        `json:"operation" example:"transfer" validate:"required"` // transfer or topup
+	Currency   string        `json:"currency" example:"IDR"`                           // Defaults to IDR
+	MinAmount  domain.Money  `json:"min_amount" swaggertype:"string" example:"0.00"`   // Lower bound of the tier
+	FlatFee    domain.Money  `json:"flat_fee" swaggertype:"string" example:"2500.00"`
+	PercentBps int           `json:"percent_bps" example:"50"`
+	MinFee     *domain.Money `json:"min_fee,omitempty" swaggertype:"string" example:"1000.00"`
+	MaxFee     *domain.Money `json:"max_fee,omitempty" swaggertype:"string" example:"25000.00"`
+}
+
+// Quote godoc
+// @Summary Quote a fee
+// @Description Show the fee of a transfer or top-up before confirming it
+// @Tags Fees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FeeQuoteRequest true "Fee Quote Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.FeeQuote}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /fees/quote [post]
+func (h *FeeHandler) Quote(c *fiber.Ctx) error {
+	var req FeeQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	operation := domain.FeeOperation(strings.ToLower(req.Operation))
+	quote, err := h.Service.Quote(c.Context(), operation, withCurrency(req.Amount, req.Currency))
+	if err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.Success(c, fiber.StatusOK, "Fee quoted", quote)
+}
+
+// ListRules godoc
+// @Summary List fee rules
+// @Description List every fee rule, grouped by operation and currency in tier order
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.FeeRule}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /admin/fees [get]
+func (h *FeeHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.Service.ListRules(c.Context())
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve fee rules", err.Error())
+	}
+	return utils.Success(c, fiber.StatusOK, "Fee rules retrieved", rules)
+}
+
+// SetRule godoc
+// @Summary Set fee rule
+// @Description Create or replace the fee tier of an operation starting at min_amount. The fee is flat_fee plus percent_bps of the amount, kept between min_fee and max_fee.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body SetFeeRuleRequest true "Fee Rule"
+// @Success 200 {object} utils.ApiResponse{data=domain.FeeRule}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/fees [put]
+func (h *FeeHandler) SetRule(c *fiber.Ctx) error {
+	var req SetFeeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	rule := &domain.FeeRule{
+		Operation:  domain.FeeOperation(strings.ToLower(req.Operation)),
+		Currency:   req.Currency,
+		MinAmount:  req.MinAmount,
+		FlatFee:    req.FlatFee,
+		PercentBps: req.PercentBps,
+		MinFee:     req.MinFee,
+		MaxFee:     req.MaxFee,
+	}
+	if err := h.Service.SetRule(c.Context(), rule); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.Success(c, fiber.StatusOK, "Fee rule saved", rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete fee rule
+// @Description Remove a fee tier
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Fee Rule ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/fees/{id} [delete]
+func (h *FeeHandler) DeleteRule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid fee rule ID", nil)
+	}
+	if err := h.Service.DeleteRule(c.Context(), int64(id)); err != nil {
+		return utils.InternalServerError(c, "Failed to delete fee rule", err.Error())
+	}
+	return utils.Success(c, fiber.StatusOK, "Fee rule deleted", nil)
+}
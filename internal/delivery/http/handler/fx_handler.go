@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FXHandler struct {
+	Service domain.FXService
+}
+
+func NewFXHandler(s domain.FXService) *FXHandler {
+	return &FXHandler{Service: s}
+}
+
+type QuoteRequest struct {
+	Amount         domain.Money `json:"amount" swaggertype:"string" example:"1000000.00" validate:"required"`
+	SourceCurrency string       `json:"source_currency" example:"IDR" validate:"required"`
+	TargetCurrency string       `json:"target_currency" example:"USD" validate:"required"`
+}
+
+type SetRateRequest struct {
+	BaseCurrency  string      `json:"base_currency" example:"USD" validate:"required"`
+	QuoteCurrency string      `json:"quote_currency" example:"IDR" validate:"required"`
+	Rate          domain.Rate `json:"rate" swaggertype:"string" example:"15650.00" validate:"required"`
+	SpreadBps     int         `json:"spread_bps" example:"50"`
+}
+
+// ListRates godoc
+// @Summary List exchange rates
+// @Description List the locally stored mid rates and their spreads
+// @Tags FX
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.ApiResponse{data=[]domain.FXRate}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /fx/rates [get]
+func (h *FXHandler) ListRates(c *fiber.Ctx) error {
+	rates, err := h.Service.ListRates(c.Context())
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve rates", err.Error())
+	}
+	return utils.Success(c, fiber.StatusOK, "Rates retrieved", rates)
+}
+
+// CreateQuote godoc
+// @Summary Create FX quote
+// @Description Lock a conversion rate for a short time. Use the quote ID with /transactions/transfer/fx.
+// @Tags FX
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body QuoteRequest true "Quote Request"
+// @Success 201 {object} utils.ApiResponse{data=domain.FXQuote}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Router /fx/quotes [post]
+func (h *FXHandler) CreateQuote(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req QuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	quote, err := h.Service.CreateQuote(c.Context(), userID, withCurrency(req.Amount, req.SourceCurrency), req.TargetCurrency)
+	if err != nil {
+		if errors.Is(err, domain.ErrRateNotFound) {
+			return utils.NotFound(c, err.Error())
+		}
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Created(c, "Quote created", quote)
+}
+
+// SetRate godoc
+// @Summary Set exchange rate
+// @Description Create or replace the mid rate and spread of a currency pair
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body SetRateRequest true "Rate"
+// @Success 200 {object} utils.ApiResponse{data=domain.FXRate}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/fx/rates [put]
+func (h *FXHandler) SetRate(c *fiber.Ctx) error {
+	var req SetRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequest(c, "Invalid request body", err.Error())
+	}
+
+	rate := &domain.FXRate{
+		BaseCurrency:  req.BaseCurrency,
+		QuoteCurrency: req.QuoteCurrency,
+		Rate:          req.Rate,
+		SpreadBps:     req.SpreadBps,
+	}
+	if err := h.Service.SetRate(c.Context(), rate); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Rate saved", rate)
+}
+
+// ImportRates godoc
+// @Summary Import exchange rates
+// @Description Import rates from a CSV body with columns base_currency,quote_currency,rate[,spread_bps]
+// @Tags Admin
+// @Accept text/csv
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body string true "CSV rates"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/fx/rates/import [post]
+func (h *FXHandler) ImportRates(c *fiber.Ctx) error {
+	imported, err := h.Service.ImportRatesCSV(c.Context(), bytes.NewReader(c.Body()))
+	if err != nil {
+		return utils.BadRequest(c, "Failed to import rates", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Rates imported", fiber.Map{
+		"imported": imported,
+	})
+}
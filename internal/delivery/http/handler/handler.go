@@ -2,13 +2,27 @@ package handler
 
 import (
 	"database/sql"
+	"os"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/payout"
 	"wallet-api/internal/repository"
 	"wallet-api/internal/service"
 )
 
 type AllHandlers struct {
-	AuthHandler   *AuthHandler
-	WalletHandler *WalletHandler
+	AuthHandler           *AuthHandler
+	WalletHandler         *WalletHandler
+	FXHandler             *FXHandler
+	LimitHandler          *LimitHandler
+	FeeHandler            *FeeHandler
+	ScheduleHandler       *ScheduleHandler
+	PaymentRequestHandler *PaymentRequestHandler
+	WalletStatusHandler   *WalletStatusHandler
+	QRPaymentHandler      *QRPaymentHandler
+	StatementHandler      *StatementHandler
+
+	IdempotencyService domain.IdempotencyService // Backs the Idempotency-Key middleware of money-moving routes
 }
 
 // InitHandlers assembles the dependency graph: Repository -> Service -> Handler
@@ -17,18 +31,59 @@ func InitHandlers(db *sql.DB) *AllHandlers {
 	userRepo := repository.NewMysqlUserRepository(db)
 	walletRepo := repository.NewMysqlWalletRepository(db)
 	transactionRepo := repository.NewMysqlTransactionRepository(db)
+	ledgerRepo := repository.NewMysqlLedgerRepository(db)
+	fxRepo := repository.NewMysqlFXRepository(db)
+	holdRepo := repository.NewMysqlHoldRepository(db)
+	scheduleRepo := repository.NewMysqlScheduleRepository(db)
+	paymentRequestRepo := repository.NewMysqlPaymentRequestRepository(db)
+	limitRepo := repository.NewMysqlLimitRepository(db)
+	feeRuleRepo := repository.NewMysqlFeeRuleRepository(db)
+	walletStatusEventRepo := repository.NewMysqlWalletStatusEventRepository(db)
+	qrCodeRepo := repository.NewMysqlQRCodeRepository(db)
+	idempotencyKeyRepo := repository.NewMysqlIdempotencyKeyRepository(db)
 
 	// 2. Initialize Services
+	payoutProvider := payout.NewLogProvider(os.Getenv("PAYOUT_LOG_FILE"))
+	quoteTTL, _ := time.ParseDuration(os.Getenv("FX_QUOTE_TTL")) // Falls back to the service default
 	authService := service.NewUserService(userRepo)
-	walletService := service.NewWalletService(db, walletRepo, transactionRepo)
+	feeService := service.NewFeeService(feeRuleRepo)
+	walletService := service.NewWalletService(db, walletRepo, transactionRepo, ledgerRepo, fxRepo, holdRepo, limitRepo, feeService, payoutProvider)
+	fxService := service.NewFXService(fxRepo, quoteTTL)
+	limitService := service.NewLimitService(limitRepo)
+	scheduleService := service.NewScheduleService(db, scheduleRepo, walletService)
+	paymentRequestTTL, _ := time.ParseDuration(os.Getenv("PAYMENT_REQUEST_TTL")) // Falls back to the service default
+	paymentRequestService := service.NewPaymentRequestService(db, paymentRequestRepo, walletService, paymentRequestTTL)
+	walletStatusService := service.NewWalletStatusService(db, walletRepo, walletStatusEventRepo)
+	qrCodeTTL, _ := time.ParseDuration(os.Getenv("QR_CODE_TTL")) // Falls back to the service default
+	qrPaymentService := service.NewQRPaymentService(db, qrCodeRepo, walletRepo, userRepo, walletService, qrCodeTTL)
+	statementService := service.NewStatementService(walletRepo, transactionRepo, userRepo)
+	idempotencyKeyTTL, _ := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")) // Falls back to the service default
+	idempotencyService := service.NewIdempotencyService(idempotencyKeyRepo, idempotencyKeyTTL)
 
 	// 3. Initialize Handlers
 	authHandler := NewAuthHandler(authService)
 	// Make sure NewWalletHandler accepts the concrete interface returned by NewWalletService
-	walletHandler := NewWalletHandler(walletService)
+	walletHandler := NewWalletHandler(walletService, authService)
+	fxHandler := NewFXHandler(fxService)
+	limitHandler := NewLimitHandler(limitService)
+	feeHandler := NewFeeHandler(feeService)
+	scheduleHandler := NewScheduleHandler(scheduleService)
+	paymentRequestHandler := NewPaymentRequestHandler(paymentRequestService)
+	walletStatusHandler := NewWalletStatusHandler(walletStatusService)
+	qrPaymentHandler := NewQRPaymentHandler(qrPaymentService)
+	statementHandler := NewStatementHandler(statementService)
 
 	return &AllHandlers{
-		AuthHandler:   authHandler,
-		WalletHandler: walletHandler,
+		AuthHandler:           authHandler,
+		WalletHandler:         walletHandler,
+		FXHandler:             fxHandler,
+		LimitHandler:          limitHandler,
+		FeeHandler:            feeHandler,
+		ScheduleHandler:       scheduleHandler,
+		PaymentRequestHandler: paymentRequestHandler,
+		WalletStatusHandler:   walletStatusHandler,
+		QRPaymentHandler:      qrPaymentHandler,
+		StatementHandler:      statementHandler,
+		IdempotencyService:    idempotencyService,
 	}
 }
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// exportFlushRows is how many rows an export buffers before pushing them to the client
+	exportFlushRows = 100
+	// exportTimeout bounds how long one export may keep streaming
+	exportTimeout = 10 * time.Minute
+)
+
+var exportCSVHeader = []string{
+	"id", "created_at", "type", "direction", "status", "amount", "currency", "counter_amount", "counter_currency",
+	"sender_wallet_id", "receiver_wallet_id", "batch_id", "reversal_of_id", "fee_for_id", "failure_reason",
+	"description", "client_reference",
+}
+
+// ExportHistory godoc
+// @Summary Export transaction history
+// @Description Stream every transaction of the logged-in user that matches the filters, newest first, as CSV or as a JSON array. Takes the same filters as the history endpoint, without paging.
+// @Tags Wallet
+// @Produce text/csv,application/json
+// @Security BearerAuth
+// @Param format query string false "csv or json" Enums(csv, json)
+// @Param currency query string false "Only wallets of this currency"
+// @Param from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Created up to, a whole day when given as YYYY-MM-DD (YYYY-MM-DD or RFC 3339)"
+// @Param direction query string false "in or out" Enums(in, out)
+// @Param status query string false "Transaction status" Enums(pending, success, failed)
+// @Param type query string false "Transaction type" Enums(topup, transfer, withdrawal, fee, adjustment, refund, capture, pocket)
+// @Param counterparty query string false "Other side by email, phone or @handle"
+// @Param reference query string false "Client reference attached by the sender"
+// @Param min_amount query string false "Smallest amount, inclusive"
+// @Param max_amount query string false "Largest amount, inclusive"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /transactions/export [get]
+func (h *WalletHandler) ExportHistory(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	format := strings.ToLower(c.Query("format", "csv"))
+	if format != "csv" && format != "json" {
+		return utils.BadRequest(c, "format must be csv or json", nil)
+	}
+	filter, err := h.historyFilter(c)
+	if err != nil {
+		return historyError(c, err)
+	}
+	// The body is streamed after the handler returns, when fiber may already have recycled the
+	// request context, so the export runs on a context of its own
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	stream, err := h.Service.ExportHistory(ctx, userID, filter)
+	if err != nil {
+		cancel()
+		return historyError(c, err)
+	}
+
+	// Headers are sent with the first flush; an error after that can only cut the body short
+	c.Attachment(fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102-150405"), format))
+	if format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	}
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		var err error
+		if format == "csv" {
+			err = exportCSV(w, stream)
+		} else {
+			err = exportJSON(w, stream)
+		}
+		if err != nil {
+			utils.LogErrorf("transaction export for user %d stopped: %v", userID, err)
+		}
+	})
+	return nil
+}
+
+func exportCSV(w *bufio.Writer, stream domain.TransactionStream) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	rows := 0
+	err := stream(func(transaction domain.Transaction) error {
+		if err := writer.Write(exportCSVRow(&transaction)); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+		return nil
+	})
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func exportJSON(w *bufio.Writer, stream domain.TransactionStream) error {
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+	rows := 0
+	err := stream(func(transaction domain.Transaction) error {
+		if rows > 0 {
+			if _, err := w.WriteString(","); err != nil {
+				return err
+			}
+		}
+		body, err := json.Marshal(transaction)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(body); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			return w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err // Leaves the array unterminated so the client sees the export is incomplete
+	}
+	_, err = w.WriteString("]")
+	return err
+}
+
+func exportCSVRow(t *domain.Transaction) []string {
+	counterAmount, counterCurrency := "", ""
+	if t.CounterAmount != nil {
+		counterAmount, counterCurrency = t.CounterAmount.String(), t.CounterAmount.Currency
+	}
+	return []string{
+		strconv.FormatInt(t.ID, 10),
+		t.CreatedAt.Format(time.RFC3339),
+		string(t.Type),
+		string(t.Direction),
+		string(t.Status),
+		t.Amount.String(),
+		t.Amount.Currency,
+		counterAmount,
+		counterCurrency,
+		optionalID(t.SenderWalletID),
+		optionalID(t.ReceiverWalletID),
+		optionalText(t.BatchID),
+		optionalID(t.ReversalOfID),
+		optionalID(t.FeeForID),
+		utils.CSVText(optionalText(t.FailureReason)),
+		utils.CSVText(optionalText(t.Description)),
+		utils.CSVText(optionalText(t.ClientReference)),
+	}
+}
+
+// historyFilter reads the history filters from the query string. A counterparty is resolved
+// the same way as a transfer receiver.
+func (h *WalletHandler) historyFilter(c *fiber.Ctx) (domain.TransactionFilter, error) {
+	filter := domain.TransactionFilter{
+		Currency:  strings.ToUpper(c.Query("currency")),
+		Direction: domain.TransactionDirection(strings.ToLower(c.Query("direction"))),
+		Status:    domain.TransactionStatus(strings.ToLower(c.Query("status"))),
+		Type:      domain.TransactionType(strings.ToLower(c.Query("type"))),
+		Reference: strings.TrimSpace(c.Query("reference")),
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryTime(c, "to", true); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount, err = queryAmount(c, "min_amount", filter.Currency); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = queryAmount(c, "max_amount", filter.Currency); err != nil {
+		return filter, err
+	}
+	if counterparty := c.Query("counterparty"); counterparty != "" {
+		user, err := h.Users.ResolveRecipient(c.Context(), counterparty)
+		if err != nil {
+			return filter, err
+		}
+		filter.CounterpartyUserID = user.ID
+	}
+	return filter, nil
+}
+
+// queryTime parses a date or an RFC 3339 time. A plain date given as an exclusive end covers
+// the whole day.
+func queryTime(c *fiber.Ctx, key string, end bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be YYYY-MM-DD or RFC 3339", domain.ErrInvalidFilter, key)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func queryAmount(c *fiber.Ctx, key, currency string) (*domain.Money, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := domain.ParseMoney(value, currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidFilter, key, err)
+	}
+	return &amount, nil
+}
+
+// historyError maps history and export errors to their status codes
+func historyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrRecipientNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidFilter), errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrInvalidHandle):
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.InternalServerError(c, "Failed to retrieve history", err.Error())
+}
+
+func optionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func optionalText(text *string) string {
+	if text == nil {
+		return ""
+	}
+	return *text
+}
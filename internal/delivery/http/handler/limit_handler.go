@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LimitHandler struct {
+	Service domain.LimitService
+}
+
+func NewLimitHandler(s domain.LimitService) *LimitHandler {
+	return &LimitHandler{Service: s}
+}
+
+type SetLimitRequest struct {
+	Currency          string        `json:"currency" example:"IDR"` // Defaults to IDR
+	PerTransactionMax *domain.Money `json:"per_transaction_max,omitempty" swaggertype:"string" example:"10000000.00"`
+	DailyAmountMax    *domain.Money `json:"daily_amount_max,omitempty" swaggertype:"string" example:"20000000.00"`
+	DailyCountMax     *int          `json:"daily_count_max,omitempty" example:"50"`
+	MonthlyAmountMax  *domain.Money `json:"monthly_amount_max,omitempty" swaggertype:"string" example:"100000000.00"`
+	MonthlyCountMax   *int          `json:"monthly_count_max,omitempty" example:"500"`
+}
+
+// List godoc
+// @Summary List transfer limits
+// @Description List the global limits (user_id 0) and every per-user override
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.TransferLimit}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /admin/limits [get]
+func (h *LimitHandler) List(c *fiber.Ctx) error {
+	limits, err := h.Service.List(c.Context())
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve limits", err.Error())
+	}
+	return utils.Success(c, fiber.StatusOK, "Limits retrieved", limits)
+}
+
+// SetGlobal godoc
+// @Summary Set global transfer limits
+// @Description Replace the limits applied to every user of a currency. Omitted fields are not capped.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body SetLimitRequest true "Limits"
+// @Success 200 {object} utils.ApiResponse{data=domain.TransferLimit}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/limits [put]
+func (h *LimitHandler) SetGlobal(c *fiber.Ctx) error {
+	return h.set(c, 0)
+}
+
+// GetUser godoc
+// @Summary Get effective user limits
+// @Description Show the limits applied to a user: the global limits merged with the user override
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "User ID"
+// @Param currency query string false "Currency" default(IDR)
+// @Success 200 {object} utils.ApiResponse{data=domain.TransferLimit}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/users/{id}/limits [get]
+func (h *LimitHandler) GetUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return utils.BadRequest(c, "Invalid user ID", nil)
+	}
+
+	limits, err := h.Service.Effective(c.Context(), int64(userID), c.Query("currency"))
+	if err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.Success(c, fiber.StatusOK, "Limits retrieved", limits)
+}
+
+// SetUser godoc
+// @Summary Override user transfer limits
+// @Description Replace the override of a user. Omitted fields fall back to the global limits.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "User ID"
+// @Param request body SetLimitRequest true "Limits"
+// @Success 200 {object} utils.ApiResponse{data=domain.TransferLimit}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/users/{id}/limits [put]
+func (h *LimitHandler) SetUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return utils.BadRequest(c, "Invalid user ID", nil)
+	}
+	return h.set(c, int64(userID))
+}
+
+// DeleteUser godoc
+// @Summary Remove user transfer limits
+// @Description Drop the override of a user so the global limits apply again
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "User ID"
+// @Param currency query string false "Currency" default(IDR)
+// @Success 200 {object} utils.ApiResponse
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /admin/users/{id}/limits [delete]
+func (h *LimitHandler) DeleteUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return utils.BadRequest(c, "Invalid user ID", nil)
+	}
+
+	if err := h.Service.Delete(c.Context(), int64(userID), c.Query("currency")); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.Success(c, fiber.StatusOK, "Limits removed", nil)
+}
+
+// set saves the limits in the request body for userID, 0 being the global limits
+func (h *LimitHandler) set(c *fiber.Ctx, userID int64) error {
+	var req SetLimitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	limit := &domain.TransferLimit{
+		UserID:            userID,
+		Currency:          req.Currency,
+		PerTransactionMax: req.PerTransactionMax,
+		DailyAmountMax:    req.DailyAmountMax,
+		DailyCountMax:     req.DailyCountMax,
+		MonthlyAmountMax:  req.MonthlyAmountMax,
+		MonthlyCountMax:   req.MonthlyCountMax,
+	}
+	if err := h.Service.Set(c.Context(), limit); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.Success(c, fiber.StatusOK, "Limits saved", limit)
+}
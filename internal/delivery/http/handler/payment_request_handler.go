@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentRequestHandler struct {
+	Service domain.PaymentRequestService
+}
+
+func NewPaymentRequestHandler(s domain.PaymentRequestService) *PaymentRequestHandler {
+	return &PaymentRequestHandler{Service: s}
+}
+
+type CreatePaymentRequestRequest struct {
+	PayerUserID int64        `json:"payer_user_id" validate:"required"`
+	Amount      domain.Money `json:"amount" swaggertype:"string" example:"75000.00" validate:"required"`
+	Currency    string       `json:"currency,omitempty" example:"IDR"`
+	Note        string       `json:"note" example:"Dinner on Friday"`
+}
+
+// Create godoc
+// @Summary Request money
+// @Description Ask another user to pay an amount. The request expires after the configured TTL.
+// @Tags Payment Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePaymentRequestRequest true "Payment Request"
+// @Success 201 {object} utils.ApiResponse{data=domain.PaymentRequest}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /payment-requests [post]
+func (h *PaymentRequestHandler) Create(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req CreatePaymentRequestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	request := &domain.PaymentRequest{
+		RequesterUserID: userID,
+		PayerUserID:     req.PayerUserID,
+		Amount:          withCurrency(req.Amount, req.Currency),
+		Note:            req.Note,
+	}
+	if err := h.Service.Create(c.Context(), request); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Created(c, "Payment request created", request)
+}
+
+// ListIncoming godoc
+// @Summary List incoming payment requests
+// @Description List payment requests the logged-in user has been asked to pay
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, accepted, declined, cancelled or expired"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.PaymentRequest}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /payment-requests/incoming [get]
+func (h *PaymentRequestHandler) ListIncoming(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	requests, err := h.Service.ListIncoming(c.Context(), userID, paymentRequestStatus(c))
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve payment requests", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Payment requests retrieved", requests)
+}
+
+// ListOutgoing godoc
+// @Summary List outgoing payment requests
+// @Description List payment requests the logged-in user has sent
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, accepted, declined, cancelled or expired"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.PaymentRequest}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /payment-requests/outgoing [get]
+func (h *PaymentRequestHandler) ListOutgoing(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	requests, err := h.Service.ListOutgoing(c.Context(), userID, paymentRequestStatus(c))
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve payment requests", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Payment requests retrieved", requests)
+}
+
+// Get godoc
+// @Summary Get a payment request
+// @Description Get a payment request the logged-in user sent or received
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment Request ID"
+// @Success 200 {object} utils.ApiResponse{data=domain.PaymentRequest}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Router /payment-requests/{id} [get]
+func (h *PaymentRequestHandler) Get(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid payment request id", nil)
+	}
+
+	request, err := h.Service.Get(c.Context(), userID, int64(id))
+	if err != nil {
+		return paymentRequestError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Payment request retrieved", request)
+}
+
+// Accept godoc
+// @Summary Accept a payment request
+// @Description Pay an incoming payment request with a transfer to the requester
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment Request ID"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.PaymentRequest}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Request is no longer pending"
+// @Failure 410 {object} utils.ApiResponse "Request has expired"
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED)"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /payment-requests/{id}/accept [post]
+func (h *PaymentRequestHandler) Accept(c *fiber.Ctx) error {
+	return h.respond(c, "Payment request accepted", h.Service.Accept)
+}
+
+// Decline godoc
+// @Summary Decline a payment request
+// @Description Decline an incoming payment request
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment Request ID"
+// @Success 200 {object} utils.ApiResponse{data=domain.PaymentRequest}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Request is no longer pending"
+// @Failure 410 {object} utils.ApiResponse "Request has expired"
+// @Router /payment-requests/{id}/decline [post]
+func (h *PaymentRequestHandler) Decline(c *fiber.Ctx) error {
+	return h.respond(c, "Payment request declined", h.Service.Decline)
+}
+
+// Cancel godoc
+// @Summary Cancel a payment request
+// @Description Withdraw a payment request the logged-in user sent
+// @Tags Payment Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment Request ID"
+// @Success 200 {object} utils.ApiResponse{data=domain.PaymentRequest}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Request is no longer pending"
+// @Failure 410 {object} utils.ApiResponse "Request has expired"
+// @Router /payment-requests/{id}/cancel [post]
+func (h *PaymentRequestHandler) Cancel(c *fiber.Ctx) error {
+	return h.respond(c, "Payment request cancelled", h.Service.Cancel)
+}
+
+// respond runs one of the status changes of a payment request for the logged-in user
+func (h *PaymentRequestHandler) respond(c *fiber.Ctx, message string, action func(ctx context.Context, userID, id int64) (*domain.PaymentRequest, error)) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid payment request id", nil)
+	}
+
+	request, err := action(c.Context(), userID, int64(id))
+	if err != nil {
+		return paymentRequestError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, message, request)
+}
+
+// paymentRequestStatus reads the optional status filter
+func paymentRequestStatus(c *fiber.Ctx) domain.PaymentRequestStatus {
+	return domain.PaymentRequestStatus(strings.ToLower(c.Query("status")))
+}
+
+// paymentRequestError maps payment request errors to their status codes
+func paymentRequestError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPaymentRequestNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrPaymentRequestClosed):
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	case errors.Is(err, domain.ErrPaymentRequestExpired):
+		return utils.Error(c, fiber.StatusGone, err.Error(), nil)
+	}
+	return transferError(c, err)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreatePocketRequest struct {
+	Name     string `json:"name" example:"Savings" validate:"required"`
+	Currency string `json:"currency" example:"IDR"` // Defaults to IDR
+}
+
+type RenamePocketRequest struct {
+	Name string `json:"name" example:"Holiday fund" validate:"required"`
+}
+
+type MovePocketRequest struct {
+	FromWalletID int64        `json:"from_wallet_id" example:"11" validate:"required"`
+	ToWalletID   int64        `json:"to_wallet_id" example:"12" validate:"required"`
+	Amount       domain.Money `json:"amount" swaggertype:"string" example:"250000.00" validate:"required"`
+	Currency     string       `json:"currency" example:"IDR"` // Defaults to IDR
+}
+
+// CreatePocket godoc
+// @Summary Create a pocket
+// @Description Open a named wallet, e.g. "Savings". The first wallet of a currency becomes its primary wallet and receives transfers from other users.
+// @Tags Pockets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePocketRequest true "Pocket"
+// @Success 201 {object} utils.ApiResponse{data=domain.Wallet}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Name already used by another pocket"
+// @Router /wallets/pockets [post]
+func (h *WalletHandler) CreatePocket(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req CreatePocketRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	wallet, err := h.Service.CreatePocket(c.Context(), userID, req.Name, req.Currency)
+	if err != nil {
+		return pocketError(c, err)
+	}
+
+	return utils.Created(c, "Pocket created", wallet)
+}
+
+// RenamePocket godoc
+// @Summary Rename a pocket
+// @Description Change the name of an open pocket of the logged-in user
+// @Tags Pockets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Wallet ID"
+// @Param request body RenamePocketRequest true "New name"
+// @Success 200 {object} utils.ApiResponse{data=domain.Wallet}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Pocket is archived or the name is already used"
+// @Router /wallets/pockets/{id} [put]
+func (h *WalletHandler) RenamePocket(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	walletID, err := c.ParamsInt("id")
+	if err != nil || walletID <= 0 {
+		return utils.BadRequest(c, "Invalid wallet id", nil)
+	}
+
+	var req RenamePocketRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	wallet, err := h.Service.RenamePocket(c.Context(), userID, int64(walletID), req.Name)
+	if err != nil {
+		return pocketError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Pocket renamed", wallet)
+}
+
+// ArchivePocket godoc
+// @Summary Archive a pocket
+// @Description Close an empty pocket. Archived pockets are hidden from the balance and cannot take money again. The primary wallet of a currency cannot be archived.
+// @Tags Pockets
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Wallet ID"
+// @Success 200 {object} utils.ApiResponse{data=domain.Wallet}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Pocket is primary, already archived or still holds funds"
+// @Router /wallets/pockets/{id}/archive [post]
+func (h *WalletHandler) ArchivePocket(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	walletID, err := c.ParamsInt("id")
+	if err != nil || walletID <= 0 {
+		return utils.BadRequest(c, "Invalid wallet id", nil)
+	}
+
+	wallet, err := h.Service.ArchivePocket(c.Context(), userID, int64(walletID))
+	if err != nil {
+		return pocketError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Pocket archived", wallet)
+}
+
+// MovePocket godoc
+// @Summary Move money between pockets
+// @Description Move money between two pockets of the logged-in user in the same currency. No fee is charged and transfer limits do not apply.
+// @Tags Pockets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MovePocketRequest true "Move Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 422 {object} utils.ApiResponse{error=domain.CurrencyMismatchError} "Pocket is in another currency"
+// @Router /wallets/pockets/move [post]
+func (h *WalletHandler) MovePocket(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req MovePocketRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	transaction, err := h.Service.MoveBetweenPockets(c.Context(), userID, req.FromWalletID, req.ToWalletID, withCurrency(req.Amount, req.Currency))
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Money moved", transaction)
+}
+
+// pocketError maps pocket management errors to their status codes
+func pocketError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrPocketNameTaken), errors.Is(err, domain.ErrWalletArchived),
+		errors.Is(err, domain.ErrPrimaryPocket), errors.Is(err, domain.ErrWalletNotEmpty):
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	return utils.BadRequest(c, err.Error(), nil)
+}
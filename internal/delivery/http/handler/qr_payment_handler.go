@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QRPaymentHandler struct {
+	Service domain.QRPaymentService
+}
+
+func NewQRPaymentHandler(s domain.QRPaymentService) *QRPaymentHandler {
+	return &QRPaymentHandler{Service: s}
+}
+
+type GenerateQRCodeRequest struct {
+	WalletID     int64         `json:"wallet_id,omitempty" example:"12"`                         // Defaults to the primary wallet of the currency
+	Amount       *domain.Money `json:"amount,omitempty" swaggertype:"string" example:"45000.00"` // Set for a dynamic, single-payment code
+	Currency     string        `json:"currency,omitempty" example:"IDR"`                         // Defaults to IDR
+	MerchantName string        `json:"merchant_name,omitempty" example:"Warung Budi"`            // Defaults to the user name, at most 25 characters
+	MerchantCity string        `json:"merchant_city,omitempty" example:"Jakarta"`                // At most 15 characters
+	Reference    string        `json:"reference,omitempty" example:"INV-2026-0042"`              // Bill number shown to the payer
+}
+
+type PayQRRequest struct {
+	Payload string       `json:"payload" example:"00020101021226500014ID.TLAB.WALLET...6304ABCD" validate:"required"` // Scanned QR string
+	Amount  domain.Money `json:"amount,omitempty" swaggertype:"string" example:"45000.00"`                            // Required for static codes
+}
+
+// Generate godoc
+// @Summary Generate a QR code
+// @Description Issue an EMVCo (QRIS-style) merchant-presented QR payload bound to one of the logged-in user's wallets. Without an amount the code is static and can be paid many times; with an amount it is dynamic, single-use and expires.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GenerateQRCodeRequest true "QR Code Request"
+// @Success 201 {object} utils.ApiResponse{data=domain.QRCode}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Failure 404 {object} utils.ApiResponse
+// @Router /payments/qr/codes [post]
+func (h *QRPaymentHandler) Generate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req GenerateQRCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	code := &domain.QRCode{
+		WalletID:     req.WalletID,
+		Currency:     req.Currency,
+		MerchantName: req.MerchantName,
+		MerchantCity: req.MerchantCity,
+		Reference:    optionalString(req.Reference),
+	}
+	if req.Amount != nil {
+		amount := withCurrency(*req.Amount, req.Currency)
+		code.Amount = &amount
+	}
+	if err := h.Service.Generate(c.Context(), userID, code); err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Created(c, "QR code generated", code)
+}
+
+// List godoc
+// @Summary List QR codes
+// @Description List the QR codes issued for the logged-in user's wallets, newest first
+// @Tags Payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.ApiResponse{data=[]domain.QRCode}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /payments/qr/codes [get]
+func (h *QRPaymentHandler) List(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	codes, err := h.Service.List(c.Context(), userID)
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve QR codes", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "QR codes retrieved", codes)
+}
+
+// Pay godoc
+// @Summary Pay a scanned QR code
+// @Description Decode a scanned QR payload, validate its CRC and transfer to the wallet the code is bound to. Dynamic codes carry their amount; static codes need one in the request.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PayQRRequest true "QR Payment Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse "Malformed payload or CRC mismatch"
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Dynamic code already paid"
+// @Failure 410 {object} utils.ApiResponse "Dynamic code has expired"
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED)"
+// @Router /payments/qr [post]
+func (h *QRPaymentHandler) Pay(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req PayQRRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	transaction, err := h.Service.Pay(c.Context(), userID, req.Payload, req.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrQRCodeNotFound):
+			return utils.NotFound(c, err.Error())
+		case errors.Is(err, domain.ErrQRCodePaid):
+			return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+		case errors.Is(err, domain.ErrQRCodeExpired):
+			return utils.Error(c, fiber.StatusGone, err.Error(), nil)
+		}
+		return transferError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Payment successful", transaction)
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleHandler struct {
+	Service domain.ScheduleService
+}
+
+func NewScheduleHandler(s domain.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{Service: s}
+}
+
+type CreateScheduleRequest struct {
+	ReceiverUserID int64                    `json:"receiver_user_id" validate:"required"`
+	Amount         domain.Money             `json:"amount" swaggertype:"string" example:"500000.00" validate:"required"`
+	Currency       string                   `json:"currency,omitempty" example:"IDR"`
+	Frequency      domain.ScheduleFrequency `json:"frequency" example:"monthly" validate:"required"`
+	StartAt        time.Time                `json:"start_at" example:"2026-04-01T09:00:00+07:00"` // Defaults to now
+	EndAt          *time.Time               `json:"end_at,omitempty"`
+}
+
+type UpdateScheduleRequest struct {
+	Amount    *domain.Money             `json:"amount,omitempty" swaggertype:"string" example:"750000.00"`
+	Currency  string                    `json:"currency,omitempty" example:"IDR"`
+	Frequency *domain.ScheduleFrequency `json:"frequency,omitempty" example:"monthly"`
+	StartAt   *time.Time                `json:"start_at,omitempty"` // Restarts the schedule from this time
+	EndAt     *time.Time                `json:"end_at,omitempty"`
+	Status    *domain.ScheduleStatus    `json:"status,omitempty" example:"paused"` // active or paused
+}
+
+type ScheduleDetail struct {
+	Schedule   *domain.ScheduledTransfer  `json:"schedule"`
+	Executions []domain.ScheduleExecution `json:"executions"`
+}
+
+// Create godoc
+// @Summary Schedule a transfer
+// @Description Schedule a one-off or recurring transfer. Monthly schedules run on the day of month of start_at.
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateScheduleRequest true "Schedule Request"
+// @Success 201 {object} utils.ApiResponse{data=domain.ScheduledTransfer}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Router /transactions/schedules [post]
+func (h *ScheduleHandler) Create(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req CreateScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	schedule := &domain.ScheduledTransfer{
+		UserID:         userID,
+		ReceiverUserID: req.ReceiverUserID,
+		Amount:         withCurrency(req.Amount, req.Currency),
+		Frequency:      req.Frequency,
+		StartAt:        req.StartAt,
+		EndAt:          req.EndAt,
+	}
+	if err := h.Service.Create(c.Context(), schedule); err != nil {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Created(c, "Transfer scheduled", schedule)
+}
+
+// List godoc
+// @Summary List scheduled transfers
+// @Description List the scheduled transfers of the logged-in user
+// @Tags Schedules
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.ApiResponse{data=[]domain.ScheduledTransfer}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /transactions/schedules [get]
+func (h *ScheduleHandler) List(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	schedules, err := h.Service.List(c.Context(), userID)
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve schedules", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Schedules retrieved", schedules)
+}
+
+// Get godoc
+// @Summary Get a scheduled transfer
+// @Description Get a scheduled transfer with its latest executions
+// @Tags Schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} utils.ApiResponse{data=ScheduleDetail}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Router /transactions/schedules/{id} [get]
+func (h *ScheduleHandler) Get(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid schedule id", nil)
+	}
+
+	schedule, executions, err := h.Service.Get(c.Context(), userID, int64(id))
+	if err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Schedule retrieved", ScheduleDetail{
+		Schedule:   schedule,
+		Executions: executions,
+	})
+}
+
+// Update godoc
+// @Summary Update a scheduled transfer
+// @Description Change the amount, timing or status of a scheduled transfer. Omitted fields keep their value.
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Param request body UpdateScheduleRequest true "Update Schedule Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.ScheduledTransfer}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Schedule is completed or cancelled"
+// @Router /transactions/schedules/{id} [put]
+func (h *ScheduleHandler) Update(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid schedule id", nil)
+	}
+
+	var req UpdateScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	schedule, _, err := h.Service.Get(c.Context(), userID, int64(id))
+	if err != nil {
+		return scheduleError(c, err)
+	}
+	if req.Amount != nil {
+		schedule.Amount = withCurrency(*req.Amount, req.Currency)
+		if schedule.Amount.Currency == "" {
+			schedule.Amount.Currency = schedule.Currency
+		}
+	}
+	if req.Frequency != nil {
+		schedule.Frequency = *req.Frequency
+	}
+	if req.StartAt != nil {
+		schedule.StartAt = *req.StartAt
+	}
+	if req.EndAt != nil {
+		schedule.EndAt = req.EndAt
+	}
+	if req.Status != nil {
+		schedule.Status = *req.Status
+	}
+
+	if err := h.Service.Update(c.Context(), userID, schedule); err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Schedule updated", schedule)
+}
+
+// Cancel godoc
+// @Summary Cancel a scheduled transfer
+// @Description Stop a scheduled transfer for good. Its execution history is kept.
+// @Tags Schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Schedule is completed or cancelled"
+// @Router /transactions/schedules/{id} [delete]
+func (h *ScheduleHandler) Cancel(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequest(c, "Invalid schedule id", nil)
+	}
+
+	if err := h.Service.Cancel(c.Context(), userID, int64(id)); err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Schedule cancelled", nil)
+}
+
+// scheduleError maps schedule errors to their status codes
+func scheduleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrScheduleNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrScheduleClosed):
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	return utils.BadRequest(c, err.Error(), nil)
+}
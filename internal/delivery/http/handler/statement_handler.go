@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatementHandler struct {
+	Service domain.StatementService
+}
+
+func NewStatementHandler(s domain.StatementService) *StatementHandler {
+	return &StatementHandler{Service: s}
+}
+
+// Download godoc
+// @Summary Download a monthly statement
+// @Description Download the statement of one of the logged-in user's wallets for a month: opening balance, every transaction with the running balance and closing balance. Defaults to the primary IDR wallet and CSV.
+// @Tags Wallet
+// @Produce text/csv,application/pdf
+// @Security BearerAuth
+// @Param month query string true "Statement month (YYYY-MM)" example(2026-03)
+// @Param wallet_id query int false "Pocket to report on"
+// @Param currency query string false "Primary wallet of this currency when wallet_id is not set"
+// @Param format query string false "csv or pdf" Enums(csv, pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /wallets/statements [get]
+func (h *StatementHandler) Download(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	month, err := time.ParseInLocation("2006-01", c.Query("month"), time.Local)
+	if err != nil {
+		return utils.BadRequest(c, "month must be given as YYYY-MM", nil)
+	}
+
+	statement, err := h.Service.Generate(c.Context(), userID, int64(c.QueryInt("wallet_id", 0)), c.Query("currency"), month)
+	if err != nil {
+		return statementError(c, err)
+	}
+	return h.send(c, statement)
+}
+
+// DownloadForWallet godoc
+// @Summary Download a monthly statement of any wallet
+// @Description Download the statement of any wallet, system wallets included, for compliance reviews
+// @Tags Admin
+// @Produce text/csv,application/pdf
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Wallet ID"
+// @Param month query string true "Statement month (YYYY-MM)" example(2026-03)
+// @Param format query string false "csv or pdf" Enums(csv, pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /admin/wallets/{id}/statements [get]
+func (h *StatementHandler) DownloadForWallet(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil || walletID <= 0 {
+		return utils.BadRequest(c, "Invalid wallet id", nil)
+	}
+	month, err := time.ParseInLocation("2006-01", c.Query("month"), time.Local)
+	if err != nil {
+		return utils.BadRequest(c, "month must be given as YYYY-MM", nil)
+	}
+
+	statement, err := h.Service.GenerateForWallet(c.Context(), int64(walletID), month)
+	if err != nil {
+		return statementError(c, err)
+	}
+	return h.send(c, statement)
+}
+
+// send renders the statement in the requested format as a download
+func (h *StatementHandler) send(c *fiber.Ctx, statement *domain.Statement) error {
+	var body bytes.Buffer
+	var err error
+	contentType := "application/pdf"
+	format := strings.ToLower(c.Query("format", "csv"))
+	switch format {
+	case "csv":
+		err = h.Service.WriteCSV(&body, statement)
+		contentType = "text/csv; charset=utf-8"
+	case "pdf":
+		err = h.Service.WritePDF(&body, statement)
+	default:
+		return utils.BadRequest(c, "format must be csv or pdf", nil)
+	}
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to render statement", err.Error())
+	}
+
+	c.Attachment(fmt.Sprintf("statement-%d-%s.%s", statement.WalletID, statement.PeriodStart.Format("2006-01"), format))
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Status(fiber.StatusOK).Send(body.Bytes())
+}
+
+// statementError maps statement errors to their status codes
+func statementError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidCurrency), errors.Is(err, domain.ErrStatementPeriod):
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.InternalServerError(c, "Failed to generate statement", err.Error())
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 	"wallet-api/internal/domain"
 	"wallet-api/internal/pkg/utils"
 
@@ -9,15 +13,22 @@ import (
 
 type WalletHandler struct {
 	Service domain.TransactionService
+	Users   domain.UserService // Resolves receivers given by email, phone or handle
 }
 
-func NewWalletHandler(s domain.TransactionService) *WalletHandler {
-	return &WalletHandler{Service: s}
+func NewWalletHandler(s domain.TransactionService, users domain.UserService) *WalletHandler {
+	return &WalletHandler{Service: s, Users: users}
 }
 
 type TransferRequest struct {
-	ReceiverUserID int64   `json:"receiver_user_id" validate:"required"`
-	Amount         float64 `json:"amount" validate:"required,gt=0"`
+	WalletID        int64           `json:"wallet_id,omitempty" example:"12"`   // Pocket to pay from, defaults to the primary wallet of the currency
+	Receiver        string          `json:"receiver,omitempty" example:"@budi"` // Email, phone number or @handle of the receiver
+	ReceiverUserID  int64           `json:"receiver_user_id,omitempty"`         // Deprecated, use receiver
+	Amount          domain.Money    `json:"amount" swaggertype:"string" example:"15000.00" validate:"required"`
+	Currency        string          `json:"currency" example:"IDR"`                                      // Defaults to IDR
+	Description     string          `json:"description,omitempty" example:"Dinner at Sate Khas Senayan"` // Shown to both sides, at most 255 characters
+	ClientReference string          `json:"client_reference,omitempty" example:"ORDER-2026-0042"`        // Your own reference, unique across your wallets
+	Metadata        domain.Metadata `json:"metadata,omitempty" swaggertype:"object"`                     // Free-form JSON object, at most 4 KB
 }
 
 // TopUp godoc
@@ -30,20 +41,26 @@ type TransferRequest struct {
 // @Success 200 {string} string "TopUp Not Implemented"
 // @Router /wallets/topup [post]
 type TopUpRequest struct {
-	Amount float64 `json:"amount" validate:"required,gt=0"`
+	WalletID int64        `json:"wallet_id,omitempty" example:"12"` // Pocket to credit, defaults to the primary wallet of the currency
+	Amount   domain.Money `json:"amount" swaggertype:"string" example:"50000.00" validate:"required"`
+	Currency string       `json:"currency" example:"IDR"` // Defaults to IDR
 }
 
 // TopUp godoc
 // @Summary Top up wallet
-// @Description Top up wallet balance for logged-in user
+// @Description Top up wallet balance for logged-in user. A top-up fee, if any, is taken out of the credited amount; see /fees/quote.
 // @Tags Wallet
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body TopUpRequest true "TopUp Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
 // @Success 200 {object} utils.ApiResponse{data=domain.Wallet}
 // @Failure 400 {object} utils.ApiResponse
 // @Failure 401 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Failure 404 {object} utils.ApiResponse "Pocket not found"
+// @Failure 422 {object} utils.ApiResponse{error=domain.CurrencyMismatchError} "Pocket is in another currency"
 // @Failure 500 {object} utils.ApiResponse
 // @Router /wallets/topup [post]
 func (h *WalletHandler) TopUp(c *fiber.Ctx) error {
@@ -54,16 +71,24 @@ func (h *WalletHandler) TopUp(c *fiber.Ctx) error {
 
 	var req TopUpRequest
 	if err := c.BodyParser(&req); err != nil {
-		return utils.BadRequest(c, "Invalid request body", err.Error())
+		return badRequestBody(c, err)
 	}
 
-	// Validate amount integer check if needed, but float is standard for currency
-	// User requested "integer" but schema uses float. If strict int is needed we can cast.
-	// Assuming float is fine as per schema, but if user insists on int logic we can handle.
-	// "topup sejumlah uang (integer)" -> treating as amount.
+	// Amounts are decoded straight into minor units, see domain.Money.UnmarshalJSON
 
-	wallet, err := h.Service.TopUp(c.Context(), userID, req.Amount)
+	wallet, err := h.Service.TopUp(c.Context(), userID, req.WalletID, withCurrency(req.Amount, req.Currency))
 	if err != nil {
+		var mismatch *domain.CurrencyMismatchError
+		switch {
+		case errors.Is(err, domain.ErrFeeExceedsAmount):
+			return utils.BadRequest(c, err.Error(), nil)
+		case errors.Is(err, domain.ErrWalletNotFound):
+			return utils.NotFound(c, err.Error())
+		case walletBlocked(err):
+			return utils.Forbidden(c, err.Error())
+		case errors.As(err, &mismatch):
+			return utils.Error(c, fiber.StatusUnprocessableEntity, err.Error(), mismatch)
+		}
 		return utils.InternalServerError(c, "Failed to topup wallet", err.Error())
 	}
 
@@ -72,15 +97,20 @@ func (h *WalletHandler) TopUp(c *fiber.Ctx) error {
 
 // Transfer godoc
 // @Summary Transfer funds
-// @Description Transfer funds from logged-in user to another user
+// @Description Transfer funds from logged-in user to another user. A transfer fee, if any, is charged to the sender on top of the amount; see /fees/quote. A description, a client reference and metadata can be attached and are returned in history.
 // @Tags Wallet
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body TransferRequest true "Transfer Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
 // @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
 // @Failure 400 {object} utils.ApiResponse
 // @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse "Pocket or receiver not found"
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED) or currency mismatch between sender and receiver"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Failure 409 {object} utils.ApiResponse "client_reference already used by the sender"
 // @Router /transactions/transfer [post]
 func (h *WalletHandler) Transfer(c *fiber.Ctx) error {
 	// Parse user_id from middleware
@@ -90,30 +120,449 @@ func (h *WalletHandler) Transfer(c *fiber.Ctx) error {
 	}
 
 	var req TransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	receiverID, err := h.receiverID(c, req.Receiver, req.ReceiverUserID)
+	if err != nil {
+		return recipientError(c, err)
+	}
+
+	memo := domain.TransferMemo{
+		Description:     req.Description,
+		ClientReference: req.ClientReference,
+		Metadata:        req.Metadata,
+	}
+	transaction, err := h.Service.Transfer(c.Context(), userID, req.WalletID, receiverID, withCurrency(req.Amount, req.Currency), memo)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Transfer successful", transaction)
+}
+
+type WithdrawRequest struct {
+	Amount        domain.Money `json:"amount" swaggertype:"string" example:"100000.00" validate:"required"`
+	BankCode      string       `json:"bank_code" example:"BCA" validate:"required"`
+	AccountNumber string       `json:"account_number" example:"1234567890" validate:"required"`
+	AccountName   string       `json:"account_name" example:"John Doe" validate:"required"`
+	Currency      string       `json:"currency" example:"IDR"` // Defaults to IDR
+}
+
+// Withdraw godoc
+// @Summary Withdraw funds
+// @Description Withdraw funds to an external bank account. The funds are held while the payout is pending.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WithdrawRequest true "Withdraw Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED)"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /transactions/withdraw [post]
+func (h *WalletHandler) Withdraw(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req WithdrawRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	transaction, err := h.Service.Withdraw(c.Context(), userID, withCurrency(req.Amount, req.Currency), domain.BankAccount{
+		BankCode:      req.BankCode,
+		AccountNumber: req.AccountNumber,
+		AccountName:   req.AccountName,
+	})
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Withdrawal "+string(transaction.Status), transaction)
+}
+
+type FXTransferRequest struct {
+	ReceiverUserID int64  `json:"receiver_user_id" validate:"required"` // May be the sender to convert between own wallets
+	QuoteID        string `json:"quote_id" validate:"required"`
+}
+
+type BatchTransferItem struct {
+	Receiver       string       `json:"receiver,omitempty" example:"budi@example.com"` // Email, phone number or @handle of the receiver
+	ReceiverUserID int64        `json:"receiver_user_id,omitempty"`                    // Deprecated, use receiver
+	Amount         domain.Money `json:"amount" swaggertype:"string" example:"25000.00" validate:"required"`
+}
+
+type BatchTransferRequest struct {
+	Currency  string              `json:"currency,omitempty" example:"IDR"`
+	Transfers []BatchTransferItem `json:"transfers" validate:"required"`
+}
+
+// BatchTransfer godoc
+// @Summary Batch transfer
+// @Description Pay several recipients at once. Either every transfer is applied or none is. Each transfer is charged the transfer fee.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BatchTransferRequest true "Batch Transfer Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.BatchTransfer}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse "Receiver not found"
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED) or a receiver has no wallet in the currency"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /transactions/batch [post]
+func (h *WalletHandler) BatchTransfer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req BatchTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	legs := make([]domain.TransferLeg, 0, len(req.Transfers))
+	for i, item := range req.Transfers {
+		receiverID, err := h.receiverID(c, item.Receiver, item.ReceiverUserID)
+		if err != nil {
+			return recipientError(c, fmt.Errorf("transfer %d: %w", i+1, err))
+		}
+		legs = append(legs, domain.TransferLeg{
+			ReceiverUserID: receiverID,
+			Amount:         withCurrency(item.Amount, req.Currency),
+		})
+	}
+
+	batch, err := h.Service.BatchTransfer(c.Context(), userID, legs)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Batch transfer successful", batch)
+}
+
+// TransferWithQuote godoc
+// @Summary Cross-currency transfer
+// @Description Transfer funds in another currency using a quote from /fx/quotes
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FXTransferRequest true "FX Transfer Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED) or receiver has no wallet in the target currency"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /transactions/transfer/fx [post]
+func (h *WalletHandler) TransferWithQuote(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req FXTransferRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.BadRequest(c, "Invalid request body", err.Error())
 	}
 
-	transaction, err := h.Service.Transfer(c.Context(), userID, req.ReceiverUserID, req.Amount)
+	transaction, err := h.Service.TransferWithQuote(c.Context(), userID, req.ReceiverUserID, req.QuoteID)
 	if err != nil {
-		// Differentiate errors if possible
-		return utils.BadRequest(c, err.Error(), nil)
+		return transferError(c, err)
 	}
 
 	return utils.Success(c, fiber.StatusOK, "Transfer successful", transaction)
 }
 
+type ReverseRequest struct {
+	Amount domain.Money `json:"amount" swaggertype:"string" example:"5000.00"` // Leave empty to reverse what is left
+	Reason string       `json:"reason" example:"Duplicate payment" validate:"required"`
+}
+
+// Reverse godoc
+// @Summary Reverse a transfer
+// @Description Refund a completed transfer in full or in part. The refund moves funds from the original receiver back to the sender.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Transaction ID"
+// @Param request body ReverseRequest true "Reverse Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Transaction already fully reversed"
+// @Router /admin/transactions/{id}/reverse [post]
+func (h *WalletHandler) Reverse(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("id")
+	if err != nil || transactionID <= 0 {
+		return utils.BadRequest(c, "Invalid transaction id", nil)
+	}
+
+	var req ReverseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	refund, err := h.Service.Reverse(c.Context(), int64(transactionID), req.Amount, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTransactionNotFound):
+			return utils.NotFound(c, err.Error())
+		case errors.Is(err, domain.ErrAlreadyReversed):
+			return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+		}
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Transaction reversed", refund)
+}
+
+type SettleWithdrawalRequest struct {
+	Reference string `json:"reference" example:"BCA-20260316-000123"` // Payout provider reference
+}
+
+type FailWithdrawalRequest struct {
+	Reason string `json:"reason" example:"Account closed"`
+}
+
+// SettleWithdrawal godoc
+// @Summary Settle a pending withdrawal
+// @Description Mark a withdrawal whose payout is pending, or whose payout call failed with an unknown outcome, as paid out
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Transaction ID"
+// @Param request body SettleWithdrawalRequest false "Settle Withdrawal Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Withdrawal is no longer pending"
+// @Router /admin/transactions/{id}/settle [post]
+func (h *WalletHandler) SettleWithdrawal(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("id")
+	if err != nil || transactionID <= 0 {
+		return utils.BadRequest(c, "Invalid transaction id", nil)
+	}
+
+	var req SettleWithdrawalRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return badRequestBody(c, err)
+		}
+	}
+
+	transaction, err := h.Service.SettleWithdrawal(c.Context(), int64(transactionID), strings.TrimSpace(req.Reference))
+	if err != nil {
+		return withdrawalError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Withdrawal settled", transaction)
+}
+
+// FailWithdrawal godoc
+// @Summary Fail a pending withdrawal
+// @Description Mark a withdrawal whose payout is pending, or whose payout call failed with an unknown outcome, as failed and return the funds to the wallet
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Transaction ID"
+// @Param request body FailWithdrawalRequest false "Fail Withdrawal Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Withdrawal is no longer pending"
+// @Router /admin/transactions/{id}/fail [post]
+func (h *WalletHandler) FailWithdrawal(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("id")
+	if err != nil || transactionID <= 0 {
+		return utils.BadRequest(c, "Invalid transaction id", nil)
+	}
+
+	var req FailWithdrawalRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return badRequestBody(c, err)
+		}
+	}
+
+	transaction, err := h.Service.FailWithdrawal(c.Context(), int64(transactionID), strings.TrimSpace(req.Reason))
+	if err != nil {
+		return withdrawalError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Withdrawal failed, funds returned", transaction)
+}
+
+// withdrawalError maps errors of settling or failing a withdrawal to status codes
+func withdrawalError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrTransactionNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrTransactionNotPending):
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	return utils.InternalServerError(c, "Failed to update withdrawal", err.Error())
+}
+
+type HoldRequest struct {
+	MerchantUserID   int64        `json:"merchant_user_id" validate:"required"`
+	Amount           domain.Money `json:"amount" swaggertype:"string" example:"150000.00" validate:"required"`
+	Currency         string       `json:"currency,omitempty" example:"IDR"`
+	ExpiresInSeconds int64        `json:"expires_in_seconds,omitempty" example:"3600"` // Defaults to 7 days, at most 30 days
+}
+
+type CaptureRequest struct {
+	Amount domain.Money `json:"amount" swaggertype:"string" example:"120000.00"` // Leave empty to capture the full hold
+}
+
+// Hold godoc
+// @Summary Authorize funds for a merchant
+// @Description Reserve funds for a merchant. Held funds stay in the balance but cannot be spent until the hold is captured, voided or expires. An active hold counts towards the transfer limits.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body HoldRequest true "Hold Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 201 {object} utils.ApiResponse{data=domain.Hold}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 422 {object} utils.ApiResponse{error=domain.LimitExceededError} "Transfer limit exceeded (LIMIT_EXCEEDED) or merchant has no wallet in the currency"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /transactions/holds [post]
+func (h *WalletHandler) Hold(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+
+	var req HoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	expiresIn := time.Duration(req.ExpiresInSeconds) * time.Second
+	hold, err := h.Service.Hold(c.Context(), userID, req.MerchantUserID, withCurrency(req.Amount, req.Currency), expiresIn)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return utils.Created(c, "Funds held", hold)
+}
+
+// Capture godoc
+// @Summary Capture a hold
+// @Description Capture all or part of a hold placed for the logged-in merchant. The rest of the hold is released.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Hold ID"
+// @Param request body CaptureRequest false "Capture Request"
+// @Param Idempotency-Key header string false "Makes retries safe: a retry with the same key and body replays the first response, another body gets 422, a retry while the first request runs gets 409"
+// @Success 200 {object} utils.ApiResponse{data=domain.Transaction}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Hold already settled or expired"
+// @Failure 403 {object} utils.ApiResponse "Wallet is frozen, closed or archived"
+// @Router /transactions/holds/{id}/capture [post]
+func (h *WalletHandler) Capture(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	holdID, err := c.ParamsInt("id")
+	if err != nil || holdID <= 0 {
+		return utils.BadRequest(c, "Invalid hold id", nil)
+	}
+
+	var req CaptureRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return badRequestBody(c, err)
+		}
+	}
+
+	transaction, err := h.Service.Capture(c.Context(), userID, int64(holdID), req.Amount)
+	if err != nil {
+		return holdError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Hold captured", transaction)
+}
+
+// Void godoc
+// @Summary Void a hold
+// @Description Release a hold placed for the logged-in merchant without moving any money
+// @Tags Wallet
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Hold ID"
+// @Success 200 {object} utils.ApiResponse{data=domain.Hold}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Hold already settled or expired"
+// @Router /transactions/holds/{id}/void [post]
+func (h *WalletHandler) Void(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int64)
+	if !ok {
+		return utils.Unauthorized(c, "Invalid user session")
+	}
+	holdID, err := c.ParamsInt("id")
+	if err != nil || holdID <= 0 {
+		return utils.BadRequest(c, "Invalid hold id", nil)
+	}
+
+	hold, err := h.Service.Void(c.Context(), userID, int64(holdID))
+	if err != nil {
+		return holdError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Hold voided", hold)
+}
+
 // GetHistory godoc
 // @Summary Get transaction history
-// @Description Get transaction history for logged-in user with pagination
+// @Description Get transaction history for logged-in user, optionally filtered. Pages by page and limit, or by cursor when a cursor parameter is sent (empty for the newest page): data is then a TransactionPage with next_cursor and prev_cursor, and rows landing between two requests never shift the pages.
 // @Tags Wallet
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
-// @Success 200 {object} utils.ApiResponse{data=[]domain.Transaction}
+// @Param cursor query string false "next_cursor or prev_cursor of an earlier page; switches to cursor paging"
+// @Param currency query string false "Only wallets of this currency"
+// @Param from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Created up to, a whole day when given as YYYY-MM-DD (YYYY-MM-DD or RFC 3339)"
+// @Param direction query string false "in or out" Enums(in, out)
+// @Param status query string false "Transaction status" Enums(pending, success, failed)
+// @Param type query string false "Transaction type" Enums(topup, transfer, withdrawal, fee, adjustment, refund, capture, pocket)
+// @Param counterparty query string false "Other side by email, phone or @handle"
+// @Param reference query string false "Client reference attached by the sender"
+// @Param min_amount query string false "Smallest amount, inclusive"
+// @Param max_amount query string false "Largest amount, inclusive"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.Transaction,meta=database.Meta} "Page mode; cursor mode returns data=domain.TransactionPage without meta"
+// @Failure 400 {object} utils.ApiResponse
 // @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
 // @Failure 500 {object} utils.ApiResponse
 // @Router /transactions/history [get]
 func (h *WalletHandler) GetHistory(c *fiber.Ctx) error {
@@ -124,23 +573,40 @@ func (h *WalletHandler) GetHistory(c *fiber.Ctx) error {
 
 	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
+	if limit < 1 {
+		limit = 10
+	}
+	filter, err := h.historyFilter(c)
+	if err != nil {
+		return historyError(c, err)
+	}
+
+	// Keyset mode, opted into by sending a cursor, empty for the newest page
+	if c.Request().URI().QueryArgs().Has("cursor") {
+		history, err := h.Service.GetHistoryPage(c.Context(), userID, filter, c.Query("cursor"), limit)
+		if err != nil {
+			return historyError(c, err)
+		}
+		return utils.Success(c, fiber.StatusOK, "History retrieved", history)
+	}
 
-	history, err := h.Service.GetHistory(c.Context(), userID, page, limit)
+	history, meta, err := h.Service.GetHistory(c.Context(), userID, filter, page, limit)
 	if err != nil {
-		return utils.InternalServerError(c, "Failed to retrieve history", err.Error())
+		return historyError(c, err)
 	}
 
-	return utils.Success(c, fiber.StatusOK, "History retrieved", history)
+	return utils.SuccessWithMeta(c, fiber.StatusOK, "History retrieved", history, meta)
 }
 
 // GetBalance godoc
 // @Summary Get user balance
-// @Description Get current balance of logged-in user
+// @Description Get current balance of every open pocket of logged-in user, primary wallets first, or of a single pocket
 // @Tags Wallet
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} utils.ApiResponse{data=domain.Wallet}
+// @Param wallet_id query int false "Only this pocket"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.Wallet}
 // @Failure 401 {object} utils.ApiResponse
 // @Failure 404 {object} utils.ApiResponse
 // @Failure 500 {object} utils.ApiResponse
@@ -151,13 +617,90 @@ func (h *WalletHandler) GetBalance(c *fiber.Ctx) error {
 		return utils.Unauthorized(c, "Invalid user session")
 	}
 
-	wallet, err := h.Service.GetBalance(c.Context(), userID)
+	wallets, err := h.Service.GetBalance(c.Context(), userID, int64(c.QueryInt("wallet_id", 0)))
 	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return utils.NotFound(c, err.Error())
+		}
 		return utils.InternalServerError(c, "Failed to retrieve balance", err.Error())
 	}
-	if wallet == nil {
+	if len(wallets) == 0 {
 		return utils.NotFound(c, "Wallet not found")
 	}
 
-	return utils.Success(c, fiber.StatusOK, "Balance retrieved", wallet)
+	return utils.Success(c, fiber.StatusOK, "Balance retrieved", wallets)
+}
+
+// holdError maps hold settlement errors to their status codes
+func holdError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrHoldNotFound):
+		return utils.NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrHoldNotActive), errors.Is(err, domain.ErrHoldExpired):
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	case walletBlocked(err):
+		return utils.Forbidden(c, err.Error())
+	}
+	return utils.BadRequest(c, err.Error(), nil)
+}
+
+// transferError maps errors of money-moving calls to their status codes. A breached limit is
+// returned as 422 with the LIMIT_EXCEEDED error code and the limit that was hit, a frozen, closed
+// or archived wallet as 403.
+func transferError(c *fiber.Ctx, err error) error {
+	if walletBlocked(err) {
+		return utils.Forbidden(c, err.Error())
+	}
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return utils.NotFound(c, err.Error())
+	}
+	if errors.Is(err, domain.ErrDuplicateReference) {
+		return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	var limit *domain.LimitExceededError
+	if errors.As(err, &limit) {
+		return utils.Error(c, fiber.StatusUnprocessableEntity, err.Error(), limit)
+	}
+	var mismatch *domain.CurrencyMismatchError
+	if errors.As(err, &mismatch) {
+		return utils.Error(c, fiber.StatusUnprocessableEntity, err.Error(), mismatch)
+	}
+	return utils.BadRequest(c, err.Error(), nil)
+}
+
+// receiverID resolves the receiver of a transfer, given either by email, phone or handle or,
+// for older clients, by user id
+func (h *WalletHandler) receiverID(c *fiber.Ctx, receiver string, receiverUserID int64) (int64, error) {
+	if receiver == "" {
+		if receiverUserID <= 0 {
+			return 0, errors.New("receiver is required")
+		}
+		return receiverUserID, nil
+	}
+	user, err := h.Users.ResolveRecipient(c.Context(), receiver)
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// walletBlocked reports whether err comes from a wallet that may not move money
+func walletBlocked(err error) bool {
+	return errors.Is(err, domain.ErrWalletFrozen) || errors.Is(err, domain.ErrWalletClosed) || errors.Is(err, domain.ErrWalletArchived)
+}
+
+// badRequestBody reports a body parsing failure, surfacing amount validation errors directly
+func badRequestBody(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrInvalidAmount) || errors.Is(err, domain.ErrAmountPrecision) {
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+	return utils.BadRequest(c, "Invalid request body", err.Error())
+}
+
+// withCurrency applies the currency field of a request to its amount when given
+func withCurrency(amount domain.Money, currency string) domain.Money {
+	if currency != "" {
+		amount.Currency = strings.ToUpper(currency)
+	}
+	return amount
 }
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WalletStatusHandler struct {
+	Service domain.WalletStatusService
+}
+
+func NewWalletStatusHandler(s domain.WalletStatusService) *WalletStatusHandler {
+	return &WalletStatusHandler{Service: s}
+}
+
+type SetWalletStatusRequest struct {
+	Status domain.WalletStatus `json:"status" example:"frozen" validate:"required"` // active, debit_frozen, frozen or closed
+	Reason string              `json:"reason" example:"Account takeover reported by user" validate:"required"`
+	Actor  string              `json:"actor" example:"ops@tlab.id" validate:"required"`
+}
+
+// SetStatus godoc
+// @Summary Change wallet status
+// @Description Freeze, debit-freeze, reactivate or close a wallet. A debit-frozen wallet can still receive funds, a frozen wallet can neither send nor receive. Closing is final and needs an empty wallet.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Wallet ID"
+// @Param request body SetWalletStatusRequest true "Status Request"
+// @Success 200 {object} utils.ApiResponse{data=domain.Wallet}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Failure 409 {object} utils.ApiResponse "Wallet is closed or still holds funds"
+// @Router /admin/wallets/{id}/status [put]
+func (h *WalletStatusHandler) SetStatus(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil || walletID <= 0 {
+		return utils.BadRequest(c, "Invalid wallet id", nil)
+	}
+
+	var req SetWalletStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return badRequestBody(c, err)
+	}
+
+	wallet, err := h.Service.SetStatus(c.Context(), int64(walletID), req.Status, req.Reason, req.Actor)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWalletNotFound):
+			return utils.NotFound(c, err.Error())
+		case errors.Is(err, domain.ErrWalletClosed), errors.Is(err, domain.ErrWalletNotEmpty):
+			return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+		}
+		return utils.BadRequest(c, err.Error(), nil)
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Wallet status updated", wallet)
+}
+
+// Events godoc
+// @Summary List wallet status changes
+// @Description List every status change of a wallet, oldest first
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Wallet ID"
+// @Success 200 {object} utils.ApiResponse{data=[]domain.WalletStatusEvent}
+// @Failure 400 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse
+// @Router /admin/wallets/{id}/status-events [get]
+func (h *WalletStatusHandler) Events(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil || walletID <= 0 {
+		return utils.BadRequest(c, "Invalid wallet id", nil)
+	}
+
+	events, err := h.Service.Events(c.Context(), int64(walletID))
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return utils.NotFound(c, err.Error())
+		}
+		return utils.InternalServerError(c, "Failed to retrieve status events", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Status events retrieved", events)
+}
+
+// Feed godoc
+// @Summary Wallet status feed
+// @Description Poll status changes of all wallets. Pass the id of the last event seen as after_id to get the next ones.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param after_id query int false "Return events after this id" default(0)
+// @Param limit query int false "Page size, at most 100" default(100)
+// @Success 200 {object} utils.ApiResponse{data=[]domain.WalletStatusEvent}
+// @Failure 401 {object} utils.ApiResponse
+// @Failure 500 {object} utils.ApiResponse
+// @Router /admin/wallet-status-events [get]
+func (h *WalletStatusHandler) Feed(c *fiber.Ctx) error {
+	afterID := c.QueryInt("after_id", 0)
+	if afterID < 0 {
+		return utils.BadRequest(c, "Invalid after_id", nil)
+	}
+
+	events, err := h.Service.Feed(c.Context(), int64(afterID), c.QueryInt("limit", 0))
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to retrieve status events", err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, "Status events retrieved", events)
+}
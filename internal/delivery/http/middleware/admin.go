@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminProtected middleware checks the X-Admin-Key header against ADMIN_API_KEY.
+// Admin routes are disabled entirely when ADMIN_API_KEY is not set.
+func AdminProtected() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		adminKey := os.Getenv("ADMIN_API_KEY")
+		if adminKey == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Admin API is disabled",
+			})
+		}
+
+		provided := c.Get("X-Admin-Key")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid admin key",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key of a request that may be retried
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotent makes a money-moving route safe to retry. The first request with an
+// Idempotency-Key header runs as usual and its response is stored; a retry with the same key
+// and body gets that response back without running again. Requests without the header are not
+// affected. Must run after JWTProtected, keys are scoped per user.
+func Idempotent(service domain.IdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > domain.MaxIdempotencyKeyLength {
+			return utils.BadRequest(c, "Idempotency-Key is too long", nil)
+		}
+		userID, ok := c.Locals("user_id").(int64)
+		if !ok {
+			return utils.Unauthorized(c, "Unauthorized")
+		}
+
+		// 1. Claim the key, or find out what became of the request that did
+		record, err := service.Begin(c.Context(), userID, key, requestFingerprint(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrIdempotencyKeyReused):
+				return utils.Error(c, fiber.StatusUnprocessableEntity, err.Error(), nil)
+			case errors.Is(err, domain.ErrIdempotencyKeyInFlight):
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return utils.Error(c, fiber.StatusConflict, err.Error(), nil)
+			}
+			return utils.InternalServerError(c, "Failed to process Idempotency-Key", err.Error())
+		}
+
+		// 2. Replay the stored response of a completed request
+		if record.Status == domain.IdempotencyStatusCompleted && record.ResponseCode != nil {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(*record.ResponseCode).Send(record.ResponseBody)
+		}
+
+		// 3. Run the request and keep its response, server errors included: a request that
+		// failed late may still have moved money, so a retry must not run it again. A returned
+		// error is rendered here so its response can be kept like any other.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		code := c.Response().StatusCode()
+		body := append([]byte(nil), c.Response().Body()...) // Fiber reuses the response buffer
+		if err := service.Complete(c.Context(), record, code, body); err != nil {
+			// The key stays in flight, so retries get 409 until it expires rather than a rerun
+			utils.LogErrorf("failed to store response for idempotency key %d: %v", record.ID, err)
+		}
+		return nil
+	}
+}
+
+// requestFingerprint identifies a request by its method, URL and body, so a key reused for a
+// different request can be told apart from a retry
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{'\n'})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{'\n'})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
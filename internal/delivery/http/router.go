@@ -37,20 +37,88 @@ func SetupRouter(handlers *handler.AllHandlers) *fiber.App {
 	// Protected Routes Group
 	protected := api.Group("/", middleware.JWTProtected())
 
+	// Money-moving routes replay their response to a retried Idempotency-Key
+	idempotent := middleware.Idempotent(handlers.IdempotencyService)
+
 	// User Routes
 	userGroup := protected.Group("/users")
 	userGroup.Get("/profile", handlers.AuthHandler.GetProfile)
+	userGroup.Put("/profile/contact", handlers.AuthHandler.UpdateContact)
+	userGroup.Get("/lookup", handlers.AuthHandler.LookupPayee)
 
 	// Wallet Routes
 	walletGroup := protected.Group("/wallets")
 	// Assuming balance endpoint logic exists or will be added to handler
-	walletGroup.Post("/topup", handlers.WalletHandler.TopUp)
+	walletGroup.Post("/topup", idempotent, handlers.WalletHandler.TopUp)
 	walletGroup.Get("/balance", handlers.WalletHandler.GetBalance)
+	walletGroup.Get("/statements", handlers.StatementHandler.Download)
+	walletGroup.Post("/pockets", handlers.WalletHandler.CreatePocket)
+	walletGroup.Post("/pockets/move", idempotent, handlers.WalletHandler.MovePocket)
+	walletGroup.Put("/pockets/:id", handlers.WalletHandler.RenamePocket)
+	walletGroup.Post("/pockets/:id/archive", handlers.WalletHandler.ArchivePocket)
 
 	// Transaction Routes
 	transactionGroup := protected.Group("/transactions")
-	transactionGroup.Post("/transfer", handlers.WalletHandler.Transfer)
+	transactionGroup.Post("/transfer", idempotent, handlers.WalletHandler.Transfer)
+	transactionGroup.Post("/transfer/fx", idempotent, handlers.WalletHandler.TransferWithQuote)
+	transactionGroup.Post("/batch", idempotent, handlers.WalletHandler.BatchTransfer)
+	transactionGroup.Post("/withdraw", idempotent, handlers.WalletHandler.Withdraw)
 	transactionGroup.Get("/history", handlers.WalletHandler.GetHistory) // Can act as history
+	transactionGroup.Get("/export", handlers.WalletHandler.ExportHistory)
+	transactionGroup.Post("/holds", idempotent, handlers.WalletHandler.Hold)
+	transactionGroup.Post("/holds/:id/capture", idempotent, handlers.WalletHandler.Capture)
+	transactionGroup.Post("/holds/:id/void", handlers.WalletHandler.Void)
+
+	// Scheduled Transfer Routes
+	transactionGroup.Post("/schedules", handlers.ScheduleHandler.Create)
+	transactionGroup.Get("/schedules", handlers.ScheduleHandler.List)
+	transactionGroup.Get("/schedules/:id", handlers.ScheduleHandler.Get)
+	transactionGroup.Put("/schedules/:id", handlers.ScheduleHandler.Update)
+	transactionGroup.Delete("/schedules/:id", handlers.ScheduleHandler.Cancel)
+
+	// Payment Request Routes
+	paymentRequestGroup := protected.Group("/payment-requests")
+	paymentRequestGroup.Post("/", handlers.PaymentRequestHandler.Create)
+	paymentRequestGroup.Get("/incoming", handlers.PaymentRequestHandler.ListIncoming)
+	paymentRequestGroup.Get("/outgoing", handlers.PaymentRequestHandler.ListOutgoing)
+	paymentRequestGroup.Get("/:id", handlers.PaymentRequestHandler.Get)
+	paymentRequestGroup.Post("/:id/accept", idempotent, handlers.PaymentRequestHandler.Accept)
+	paymentRequestGroup.Post("/:id/decline", handlers.PaymentRequestHandler.Decline)
+	paymentRequestGroup.Post("/:id/cancel", handlers.PaymentRequestHandler.Cancel)
+
+	// QR Payment Routes
+	paymentGroup := protected.Group("/payments")
+	paymentGroup.Post("/qr", idempotent, handlers.QRPaymentHandler.Pay)
+	paymentGroup.Post("/qr/codes", handlers.QRPaymentHandler.Generate)
+	paymentGroup.Get("/qr/codes", handlers.QRPaymentHandler.List)
+
+	// FX Routes
+	fxGroup := protected.Group("/fx")
+	fxGroup.Get("/rates", handlers.FXHandler.ListRates)
+	fxGroup.Post("/quotes", handlers.FXHandler.CreateQuote)
+
+	// Fee Routes
+	protected.Post("/fees/quote", handlers.FeeHandler.Quote)
+
+	// Admin Routes (X-Admin-Key)
+	adminGroup := api.Group("/admin", middleware.AdminProtected())
+	adminGroup.Put("/fx/rates", handlers.FXHandler.SetRate)
+	adminGroup.Post("/fx/rates/import", handlers.FXHandler.ImportRates)
+	adminGroup.Post("/transactions/:id/reverse", handlers.WalletHandler.Reverse)
+	adminGroup.Post("/transactions/:id/settle", handlers.WalletHandler.SettleWithdrawal)
+	adminGroup.Post("/transactions/:id/fail", handlers.WalletHandler.FailWithdrawal)
+	adminGroup.Get("/limits", handlers.LimitHandler.List)
+	adminGroup.Put("/limits", handlers.LimitHandler.SetGlobal)
+	adminGroup.Get("/users/:id/limits", handlers.LimitHandler.GetUser)
+	adminGroup.Put("/users/:id/limits", handlers.LimitHandler.SetUser)
+	adminGroup.Delete("/users/:id/limits", handlers.LimitHandler.DeleteUser)
+	adminGroup.Get("/fees", handlers.FeeHandler.ListRules)
+	adminGroup.Put("/fees", handlers.FeeHandler.SetRule)
+	adminGroup.Delete("/fees/:id", handlers.FeeHandler.DeleteRule)
+	adminGroup.Put("/wallets/:id/status", handlers.WalletStatusHandler.SetStatus)
+	adminGroup.Get("/wallets/:id/status-events", handlers.WalletStatusHandler.Events)
+	adminGroup.Get("/wallets/:id/statements", handlers.StatementHandler.DownloadForWallet)
+	adminGroup.Get("/wallet-status-events", handlers.WalletStatusHandler.Feed)
 
 	// Health Check
 	app.Get("/health", func(c *fiber.Ctx) error {
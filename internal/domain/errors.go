@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnbalancedJournal      = errors.New("unbalanced ledger journal")
+	ErrInsufficientBalance    = errors.New("insufficient balance")
+	ErrWalletNotFound         = errors.New("wallet not found")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrTransactionNotPending  = errors.New("transaction is not pending")
+	ErrInvalidCurrency        = errors.New("invalid currency")
+	ErrCurrencyMismatch       = errors.New("currency mismatch")
+	ErrRateNotFound           = errors.New("exchange rate not found")
+	ErrQuoteNotFound          = errors.New("quote not found")
+	ErrQuoteExpired           = errors.New("quote has expired")
+	ErrQuoteUsed              = errors.New("quote has already been used")
+	ErrNotReversible          = errors.New("only completed same-currency transfers can be reversed")
+	ErrAlreadyReversed        = errors.New("transaction has already been fully reversed")
+	ErrReversalExceeds        = errors.New("reversal amount exceeds what is left of the original transaction")
+	ErrHoldNotFound           = errors.New("hold not found")
+	ErrHoldNotActive          = errors.New("hold is no longer active")
+	ErrHoldExpired            = errors.New("hold has expired")
+	ErrCaptureExceedsHold     = errors.New("capture amount exceeds the held amount")
+	ErrEmptyBatch             = errors.New("batch must contain at least one transfer")
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrPaymentRequestClosed   = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired  = errors.New("payment request has expired")
+	ErrScheduleNotFound       = errors.New("scheduled transfer not found")
+	ErrScheduleClosed         = errors.New("scheduled transfer is completed or cancelled")
+	ErrLimitExceeded          = errors.New("transfer limit exceeded")
+	ErrFeeExceedsAmount       = errors.New("fee would consume the whole amount")
+	ErrWalletFrozen           = errors.New("wallet is frozen")
+	ErrWalletClosed           = errors.New("wallet is closed")
+	ErrWalletNotEmpty         = errors.New("wallet still holds funds")
+	ErrWalletArchived         = errors.New("wallet is archived")
+	ErrPrimaryPocket          = errors.New("the primary wallet of a currency cannot be archived")
+	ErrPocketNameTaken        = errors.New("a pocket with this name already exists")
+	ErrInvalidWalletStatus    = errors.New("invalid wallet status")
+	ErrRecipientNotFound      = errors.New("recipient not found")
+	ErrInvalidPhone           = errors.New("invalid phone number")
+	ErrInvalidHandle          = errors.New("invalid handle")
+	ErrPhoneTaken             = errors.New("phone number already registered")
+	ErrHandleTaken            = errors.New("handle already taken")
+	ErrInvalidQRCode          = errors.New("invalid QR code")
+	ErrQRCodeNotFound         = errors.New("QR code not found")
+	ErrQRCodePaid             = errors.New("QR code has already been paid")
+	ErrQRCodeExpired          = errors.New("QR code has expired")
+	ErrStatementPeriod        = errors.New("statement month has not started yet")
+	ErrInvalidFilter          = errors.New("invalid transaction filter")
+	ErrInvalidCursor          = errors.New("invalid cursor")
+	ErrInvalidMemo            = errors.New("invalid transfer memo")
+	ErrDuplicateReference     = errors.New("client_reference has already been used by the sender")
+	ErrIdempotencyKeyReused   = errors.New("idempotency key has already been used for a different request")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still being processed")
+)
+
+// CurrencyMismatchError is returned when money would move between wallets of different currencies
+type CurrencyMismatchError struct {
+	SourceCurrency string `json:"source_currency"`
+	TargetCurrency string `json:"target_currency"`
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	if e.TargetCurrency == "" {
+		return fmt.Sprintf("currency mismatch: no %s wallet to receive the funds", e.SourceCurrency)
+	}
+	return fmt.Sprintf("currency mismatch: cannot move %s into a %s wallet", e.SourceCurrency, e.TargetCurrency)
+}
+
+// Is makes errors.Is(err, ErrCurrencyMismatch) match
+func (e *CurrencyMismatchError) Is(target error) bool {
+	return target == ErrCurrencyMismatch
+}
+
+// LimitExceededCode is the error code returned to clients when a limit is breached
+const LimitExceededCode = "LIMIT_EXCEEDED"
+
+// Names of the limits a transfer can breach
+const (
+	LimitPerTransaction = "per_transaction"
+	LimitDailyAmount    = "daily_amount"
+	LimitDailyCount     = "daily_count"
+	LimitMonthlyAmount  = "monthly_amount"
+	LimitMonthlyCount   = "monthly_count"
+)
+
+// LimitExceededError tells which limit a transfer would breach
+type LimitExceededError struct {
+	Code  string `json:"error_code"`
+	Limit string `json:"limit"`
+	Max   string `json:"max"`
+	Used  string `json:"used"` // Already used in the period, empty for the per-transaction limit
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Used == "" {
+		return fmt.Sprintf("%s limit of %s exceeded", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("%s limit of %s exceeded, %s already used", e.Limit, e.Max, e.Used)
+}
+
+// Is makes errors.Is(err, ErrLimitExceeded) match
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
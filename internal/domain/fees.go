@@ -0,0 +1,20 @@
+package domain
+
+import "math/big"
+
+// Apply prices amount: the flat fee plus PercentBps of the amount, rounded half up to the minor
+// unit, then kept between MinFee and MaxFee
+func (r FeeRule) Apply(amount Money) Money {
+	percent := new(big.Int).Mul(big.NewInt(amount.MinorUnits), big.NewInt(int64(r.PercentBps)))
+	percent.Add(percent, big.NewInt(5_000))
+	percent.Quo(percent, big.NewInt(10_000))
+
+	fee := NewMoney(r.FlatFee.MinorUnits+percent.Int64(), amount.Currency)
+	if r.MinFee != nil && fee.LessThan(*r.MinFee) {
+		fee.MinorUnits = r.MinFee.MinorUnits
+	}
+	if r.MaxFee != nil && r.MaxFee.LessThan(fee) {
+		fee.MinorUnits = r.MaxFee.MinorUnits
+	}
+	return fee
+}
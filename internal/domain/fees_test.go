@@ -0,0 +1,31 @@
+package domain
+
+import "testing"
+
+func TestFeeRuleApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   FeeRule
+		amount string
+		want   string
+	}{
+		{"flat only", FeeRule{FlatFee: MustParseMoney("2500", "IDR")}, "100000", "2500.00"},
+		{"free", FeeRule{}, "100000", "0.00"},
+		{"percent", FeeRule{PercentBps: 70}, "100000", "700.00"},
+		{"flat plus percent", FeeRule{FlatFee: MustParseMoney("1000", "IDR"), PercentBps: 50}, "200000", "2000.00"},
+		// 0.7% of 0.75 is 0.00525 and rounds up; 0.7% of 0.71 is 0.00497 and rounds down
+		{"rounds up above half", FeeRule{PercentBps: 70}, "0.75", "0.01"},
+		{"rounds down below half", FeeRule{PercentBps: 70}, "0.71", "0.00"},
+		{"exactly half rounds up", FeeRule{PercentBps: 50}, "1.00", "0.01"},
+		{"clamped to min", FeeRule{PercentBps: 10, MinFee: moneyPtr("1000")}, "50000", "1000.00"},
+		{"clamped to max", FeeRule{PercentBps: 100, MaxFee: moneyPtr("25000")}, "10000000", "25000.00"},
+		{"between min and max", FeeRule{PercentBps: 100, MinFee: moneyPtr("1000"), MaxFee: moneyPtr("25000")}, "500000", "5000.00"},
+		{"flat counts towards max", FeeRule{FlatFee: MustParseMoney("20000", "IDR"), PercentBps: 100, MaxFee: moneyPtr("25000")}, "1000000", "25000.00"},
+	}
+	for _, tt := range tests {
+		got := tt.rule.Apply(MustParseMoney(tt.amount, "IDR"))
+		if got.String() != tt.want || got.Currency != "IDR" {
+			t.Errorf("%s: Apply(%s) = %s %s, want %s IDR", tt.name, tt.amount, got, got.Currency, tt.want)
+		}
+	}
+}
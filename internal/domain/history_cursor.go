@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryCursor marks a position in a transaction history ordered by (created_at, id), newest
+// first. Clients only see it encoded and hand it back unchanged.
+type HistoryCursor struct {
+	CreatedAt time.Time
+	ID        int64
+	Backward  bool // Read the newer rows before the position instead of the older rows after it
+}
+
+// TransactionPage is a page of a transaction history read by cursor
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"` // Older transactions, empty on the last page
+	PrevCursor   string        `json:"prev_cursor,omitempty"` // Newer transactions, empty on the first page
+}
+
+// CursorAfter points past t towards older transactions
+func CursorAfter(t *Transaction) *HistoryCursor {
+	return &HistoryCursor{CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+// CursorBefore points past t towards newer transactions
+func CursorBefore(t *Transaction) *HistoryCursor {
+	return &HistoryCursor{CreatedAt: t.CreatedAt, ID: t.ID, Backward: true}
+}
+
+// Encode returns the opaque form of the cursor
+func (c *HistoryCursor) Encode() string {
+	direction := "n"
+	if c.Backward {
+		direction = "p"
+	}
+	raw := fmt.Sprintf("%s:%d:%d", direction, c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseHistoryCursor decodes a cursor produced by Encode
+func ParseHistoryCursor(value string) (*HistoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[0] != "n" && parts[0] != "p") {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &HistoryCursor{CreatedAt: time.Unix(0, nanos), ID: id, Backward: parts[0] == "p"}, nil
+}
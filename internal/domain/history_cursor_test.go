@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	transaction := &Transaction{ID: 42, CreatedAt: time.Date(2026, 3, 1, 9, 30, 15, 123456789, time.UTC)}
+	for _, cursor := range []*HistoryCursor{CursorAfter(transaction), CursorBefore(transaction)} {
+		encoded := cursor.Encode()
+		decoded, err := ParseHistoryCursor(encoded)
+		if err != nil {
+			t.Fatalf("ParseHistoryCursor(%q) error = %v", encoded, err)
+		}
+		if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID || decoded.Backward != cursor.Backward {
+			t.Errorf("ParseHistoryCursor(Encode()) = %+v, want %+v", decoded, cursor)
+		}
+	}
+	if CursorAfter(transaction).Encode() == CursorBefore(transaction).Encode() {
+		t.Error("cursors in both directions encode the same")
+	}
+}
+
+func TestParseHistoryCursorRejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	for _, value := range []string{
+		"",
+		"not base64!",
+		encode("n:1"),
+		encode("x:1772357415000000000:42"),
+		encode("n:yesterday:42"),
+		encode("n:1772357415000000000:0"),
+		encode("n:1772357415000000000:-3"),
+		encode("n:1772357415000000000:42:extra"),
+	} {
+		if _, err := ParseHistoryCursor(value); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("ParseHistoryCursor(%q) error = %v, want ErrInvalidCursor", value, err)
+		}
+	}
+}
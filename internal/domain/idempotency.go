@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// MaxIdempotencyKeyLength is the longest Idempotency-Key header accepted
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyStatus defines possible statuses of an idempotency key
+type IdempotencyStatus string
+
+const (
+	IdempotencyStatusProcessing IdempotencyStatus = "processing" // The first request is running, or died before its response was stored
+	IdempotencyStatusCompleted  IdempotencyStatus = "completed"  // Response stored, retries replay it
+)
+
+// IdempotencyKey remembers what a user's first request with a key looked like and what it was
+// answered, so a retry with the same key gets the same answer instead of moving money again
+type IdempotencyKey struct {
+	ID           int64             `db:"id" goqu:"skipinsert"`
+	UserID       int64             `db:"user_id"`
+	Key          string            `db:"idempotency_key"`
+	Fingerprint  string            `db:"fingerprint"` // SHA-256 of method, URL and body
+	Status       IdempotencyStatus `db:"status"`
+	ResponseCode *int              `db:"response_code"`
+	ResponseBody []byte            `db:"response_body"`
+	ExpiresAt    time.Time         `db:"expires_at"`
+	CreatedAt    time.Time         `db:"created_at" goqu:"skipinsert"`
+	UpdatedAt    time.Time         `db:"updated_at" goqu:"skipinsert"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "fmt"
+
+// Merge returns the limits of l with every field set in override replacing it
+func (l TransferLimit) Merge(override *TransferLimit) TransferLimit {
+	if override == nil {
+		return l
+	}
+	merged := l
+	merged.ID = override.ID
+	merged.UserID = override.UserID
+	if override.PerTransactionMax != nil {
+		merged.PerTransactionMax = override.PerTransactionMax
+	}
+	if override.DailyAmountMax != nil {
+		merged.DailyAmountMax = override.DailyAmountMax
+	}
+	if override.DailyCountMax != nil {
+		merged.DailyCountMax = override.DailyCountMax
+	}
+	if override.MonthlyAmountMax != nil {
+		merged.MonthlyAmountMax = override.MonthlyAmountMax
+	}
+	if override.MonthlyCountMax != nil {
+		merged.MonthlyCountMax = override.MonthlyCountMax
+	}
+	return merged
+}
+
+// Check reports the first limit that count transfers worth total would breach on top of usage.
+// largest is the biggest single transfer, checked against the per-transaction maximum.
+func (l TransferLimit) Check(largest, total Money, count int, usage TransferUsage) error {
+	if l.PerTransactionMax != nil && l.PerTransactionMax.LessThan(largest) {
+		return &LimitExceededError{Code: LimitExceededCode, Limit: LimitPerTransaction, Max: l.PerTransactionMax.String()}
+	}
+	if l.DailyAmountMax != nil && l.DailyAmountMax.LessThan(usage.DailyAmount.Add(total)) {
+		return &LimitExceededError{Code: LimitExceededCode, Limit: LimitDailyAmount, Max: l.DailyAmountMax.String(), Used: usage.DailyAmount.String()}
+	}
+	if l.DailyCountMax != nil && usage.DailyCount+count > *l.DailyCountMax {
+		return &LimitExceededError{Code: LimitExceededCode, Limit: LimitDailyCount, Max: fmt.Sprint(*l.DailyCountMax), Used: fmt.Sprint(usage.DailyCount)}
+	}
+	if l.MonthlyAmountMax != nil && l.MonthlyAmountMax.LessThan(usage.MonthlyAmount.Add(total)) {
+		return &LimitExceededError{Code: LimitExceededCode, Limit: LimitMonthlyAmount, Max: l.MonthlyAmountMax.String(), Used: usage.MonthlyAmount.String()}
+	}
+	if l.MonthlyCountMax != nil && usage.MonthlyCount+count > *l.MonthlyCountMax {
+		return &LimitExceededError{Code: LimitExceededCode, Limit: LimitMonthlyCount, Max: fmt.Sprint(*l.MonthlyCountMax), Used: fmt.Sprint(usage.MonthlyCount)}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func moneyPtr(value string) *Money {
+	m := MustParseMoney(value, "IDR")
+	return &m
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestTransferLimitMerge(t *testing.T) {
+	global := TransferLimit{
+		ID:                1,
+		Currency:          "IDR",
+		PerTransactionMax: moneyPtr("10000000"),
+		DailyAmountMax:    moneyPtr("20000000"),
+		DailyCountMax:     intPtr(50),
+		MonthlyAmountMax:  moneyPtr("100000000"),
+	}
+
+	if got := global.Merge(nil); got.ID != 1 || *got.DailyCountMax != 50 {
+		t.Errorf("Merge(nil) = %+v, want the global limits", got)
+	}
+
+	merged := global.Merge(&TransferLimit{ID: 7, UserID: 42, DailyAmountMax: moneyPtr("5000000"), MonthlyCountMax: intPtr(10)})
+	if merged.ID != 7 || merged.UserID != 42 {
+		t.Errorf("Merge() ID, UserID = %d, %d; want the override's 7, 42", merged.ID, merged.UserID)
+	}
+	if merged.DailyAmountMax.String() != "5000000.00" || *merged.MonthlyCountMax != 10 {
+		t.Errorf("Merge() = %+v, want the overridden daily amount and monthly count", merged)
+	}
+	if merged.PerTransactionMax.String() != "10000000.00" || *merged.DailyCountMax != 50 || merged.MonthlyAmountMax.String() != "100000000.00" {
+		t.Errorf("Merge() = %+v, want the global limits the override leaves unset", merged)
+	}
+	if global.DailyAmountMax.String() != "20000000.00" || global.MonthlyCountMax != nil {
+		t.Errorf("Merge() changed the receiver: %+v", global)
+	}
+}
+
+func TestTransferLimitCheck(t *testing.T) {
+	limit := TransferLimit{
+		Currency:          "IDR",
+		PerTransactionMax: moneyPtr("1000"),
+		DailyAmountMax:    moneyPtr("3000"),
+		DailyCountMax:     intPtr(3),
+		MonthlyAmountMax:  moneyPtr("10000"),
+		MonthlyCountMax:   intPtr(10),
+	}
+	usage := func(daily string, dailyCount int, monthly string, monthlyCount int) TransferUsage {
+		return TransferUsage{
+			DailyAmount:   MustParseMoney(daily, "IDR"),
+			DailyCount:    dailyCount,
+			MonthlyAmount: MustParseMoney(monthly, "IDR"),
+			MonthlyCount:  monthlyCount,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		largest string
+		total   string
+		count   int
+		usage   TransferUsage
+		limit   string // Empty when the transfer fits
+		used    string
+	}{
+		{"fits", "1000", "1000", 1, usage("2000", 2, "9000", 9), "", ""},
+		{"over per transaction", "1000.01", "1000.01", 1, usage("0", 0, "0", 0), LimitPerTransaction, ""},
+		{"over daily amount", "500", "500", 1, usage("2500.01", 1, "2500.01", 1), LimitDailyAmount, "2500.01"},
+		{"over daily count", "100", "100", 1, usage("300", 3, "300", 3), LimitDailyCount, "3"},
+		{"over monthly amount", "500", "500", 1, usage("0", 0, "9500.50", 5), LimitMonthlyAmount, "9500.50"},
+		{"over monthly count", "100", "100", 1, usage("0", 0, "100", 10), LimitMonthlyCount, "10"},
+		// A batch is checked as a whole: every leg fits alone, together they breach the day
+		{"batch over daily amount", "1000", "3000.01", 4, usage("0", 0, "0", 0), LimitDailyAmount, "0.00"},
+		{"batch over daily count", "100", "400", 4, usage("0", 0, "0", 0), LimitDailyCount, "0"},
+		// The per-transaction limit is checked first
+		{"first breach wins", "5000", "5000", 1, usage("3000", 3, "10000", 10), LimitPerTransaction, ""},
+	}
+	for _, tt := range tests {
+		err := limit.Check(MustParseMoney(tt.largest, "IDR"), MustParseMoney(tt.total, "IDR"), tt.count, tt.usage)
+		if tt.limit == "" {
+			if err != nil {
+				t.Errorf("%s: Check() error = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		var exceeded *LimitExceededError
+		if !errors.As(err, &exceeded) {
+			t.Errorf("%s: Check() error = %v, want a LimitExceededError", tt.name, err)
+			continue
+		}
+		if exceeded.Limit != tt.limit || exceeded.Used != tt.used || exceeded.Code != LimitExceededCode {
+			t.Errorf("%s: Check() = %+v, want limit %s used %q", tt.name, exceeded, tt.limit, tt.used)
+		}
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: Check() error does not match ErrLimitExceeded", tt.name)
+		}
+	}
+
+	if err := (TransferLimit{}).Check(MustParseMoney("999999999", "IDR"), MustParseMoney("999999999", "IDR"), 100, usage("0", 1000, "0", 1000)); err != nil {
+		t.Errorf("Check() without limits error = %v, want nil", err)
+	}
+}
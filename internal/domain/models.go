@@ -2,7 +2,10 @@ package domain
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"time"
+	"wallet-api/internal/pkg/database"
 )
 
 // User represents a user entity
@@ -10,18 +13,83 @@ type User struct {
 	ID        int64     `json:"id" db:"id" goqu:"skipinsert"`
 	Name      string    `json:"name" db:"name" validate:"required"`
 	Email     string    `json:"email" db:"email" validate:"required,email"`
+	Phone     *string   `json:"phone,omitempty" db:"phone"`   // E.164, e.g. +6281234567890
+	Handle    *string   `json:"handle,omitempty" db:"handle"` // Public handle without the @
 	Password  string    `json:"password,omitempty" db:"password" validate:"required,min=6"`
 	CreatedAt time.Time `json:"created_at" db:"created_at" goqu:"skipinsert"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
 }
 
+// Payee is what a sender may see of a receiver before paying them
+type Payee struct {
+	DisplayName string  `json:"display_name" example:"B*** S******"` // Masked name
+	Handle      *string `json:"handle,omitempty" example:"budi"`
+}
+
 // Wallet represents a digital wallet associated with a user
 type Wallet struct {
-	ID        int64     `json:"id" db:"id" goqu:"skipinsert"`
-	UserID    int64     `json:"user_id" validate:"required" db:"user_id"`
-	Balance   float64   `json:"balance" db:"balance"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" goqu:"skipinsert"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+	ID         int64         `json:"id" db:"id" goqu:"skipinsert"`
+	UserID     *int64        `json:"user_id,omitempty" db:"user_id"`         // Nil for system wallets
+	SystemCode *SystemWallet `json:"system_code,omitempty" db:"system_code"` // Set for system wallets only
+	Currency   string        `json:"currency" db:"currency"`                 // ISO-4217
+	Name       string        `json:"name" db:"name"`                         // Pocket name chosen by the user
+	IsPrimary  bool          `json:"is_primary" db:"is_primary"`             // One primary wallet per user per currency receives transfers
+	ArchivedAt *time.Time    `json:"archived_at,omitempty" db:"archived_at"` // Archived pockets are empty and take no more money
+	Balance    Money         `json:"balance" db:"balance"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+
+	HeldBalance      Money `json:"held_balance" db:"held_balance"` // Reserved by active holds, still part of Balance
+	AvailableBalance Money `json:"available_balance" db:"-"`       // Balance minus HeldBalance
+
+	Status          WalletStatus `json:"status" db:"status"`
+	StatusReason    *string      `json:"status_reason,omitempty" db:"status_reason"`
+	StatusChangedBy *string      `json:"status_changed_by,omitempty" db:"status_changed_by"`
+	StatusChangedAt *time.Time   `json:"status_changed_at,omitempty" db:"status_changed_at"`
+}
+
+// WalletStatus controls which way money may move through a wallet
+type WalletStatus string
+
+const (
+	WalletStatusActive      WalletStatus = "active"
+	WalletStatusDebitFrozen WalletStatus = "debit_frozen" // Can receive but not send
+	WalletStatusFrozen      WalletStatus = "frozen"       // Can neither send nor receive
+	WalletStatusClosed      WalletStatus = "closed"       // Final, the wallet must be empty
+)
+
+// WalletStatusEvent records a status change of a wallet for ops tooling
+type WalletStatusEvent struct {
+	ID         int64        `json:"id" db:"id" goqu:"skipinsert"`
+	WalletID   int64        `json:"wallet_id" db:"wallet_id"`
+	FromStatus WalletStatus `json:"from_status" db:"from_status"`
+	ToStatus   WalletStatus `json:"to_status" db:"to_status"`
+	Reason     string       `json:"reason" db:"reason"`
+	Actor      string       `json:"actor" db:"actor"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+}
+
+// PrimaryPocketName names the wallet created on the first top-up of a currency
+const PrimaryPocketName = "Main"
+
+// MaxPocketNameLength matches the wallets.name column
+const MaxPocketNameLength = 50
+
+// SystemWallet names a wallet owned by the platform instead of a user. Money enters and leaves
+// user wallets through them, so the balances of all wallets of a currency always add up to zero.
+type SystemWallet string
+
+const (
+	SystemWalletTreasury      SystemWallet = "treasury"       // Counterpart of payouts to banks
+	SystemWalletTopUpClearing SystemWallet = "topup_clearing" // Counterpart of top-ups
+	SystemWalletFees          SystemWallet = "fees"           // Collects fees
+	SystemWalletSuspense      SystemWallet = "suspense"       // Counterpart of reconciliation adjustments
+	SystemWalletFXPosition    SystemWallet = "fx_position"    // Buys and sells currencies on cross-currency transfers
+)
+
+// Available is the part of the balance that is not reserved by holds
+func (w *Wallet) Available() Money {
+	return w.Balance.Sub(w.HeldBalance)
 }
 
 // TransactionStatus defines possible statuses for a transaction
@@ -33,15 +101,553 @@ const (
 	TransactionStatusFailed  TransactionStatus = "failed"
 )
 
+// TransactionType defines what kind of balance change a transaction records
+type TransactionType string
+
+const (
+	TransactionTypeTopUp      TransactionType = "topup"
+	TransactionTypeTransfer   TransactionType = "transfer"
+	TransactionTypeWithdrawal TransactionType = "withdrawal"
+	TransactionTypeFee        TransactionType = "fee"
+	TransactionTypeAdjustment TransactionType = "adjustment"
+	TransactionTypeRefund     TransactionType = "refund"
+	TransactionTypeCapture    TransactionType = "capture"  // Captured hold
+	TransactionTypePocket     TransactionType = "pocket"   // Move between two pockets of one user
+	TransactionTypeExchange   TransactionType = "exchange" // FX position wallets settling a cross-currency transfer
+)
+
+// TransactionDirection tells whether a transaction moved money into or out of the caller's wallet
+type TransactionDirection string
+
+const (
+	TransactionDirectionIn  TransactionDirection = "in"
+	TransactionDirectionOut TransactionDirection = "out"
+)
+
 // Transaction represents a financial transaction between wallets
 type Transaction struct {
-	ID               int64             `json:"id" db:"id" goqu:"skipinsert"`
-	SenderWalletID   *int64            `json:"sender_wallet_id" db:"sender_wallet_id"`     // Nullable if system sends money
-	ReceiverWalletID *int64            `json:"receiver_wallet_id" db:"receiver_wallet_id"` // Nullable if withdrawing to external
-	Amount           float64           `json:"amount" db:"amount" validate:"required,gt=0"`
-	Status           TransactionStatus `json:"status" db:"status"`
-	CreatedAt        time.Time         `json:"created_at" db:"created_at" goqu:"skipinsert"`
-	UpdatedAt        time.Time         `json:"updated_at" db:"created_at" goqu:"skipinsert"`
+	ID                int64                `json:"id" db:"id" goqu:"skipinsert"`
+	SenderWalletID    *int64               `json:"sender_wallet_id" db:"sender_wallet_id"`     // Nil only on top-ups recorded before system wallets existed
+	ReceiverWalletID  *int64               `json:"receiver_wallet_id" db:"receiver_wallet_id"` // Nil only on withdrawals recorded before system wallets existed
+	Type              TransactionType      `json:"type" db:"type"`
+	Amount            Money                `json:"amount" db:"amount" validate:"required"`
+	Currency          string               `json:"currency" db:"currency"`
+	CounterAmount     *Money               `json:"counter_amount,omitempty" db:"counter_amount"`     // Amount credited on cross-currency transfers
+	CounterCurrency   *string              `json:"counter_currency,omitempty" db:"counter_currency"` // Currency of CounterAmount
+	FxRate            *Rate                `json:"fx_rate,omitempty" db:"fx_rate" swaggertype:"string"`
+	FxSpread          *Money               `json:"fx_spread,omitempty" db:"fx_spread"`           // Spread kept by the platform, in the counter currency
+	ReversalOfID      *int64               `json:"reversal_of_id,omitempty" db:"reversal_of_id"` // Original transaction of a refund
+	ReversalReason    *string              `json:"reversal_reason,omitempty" db:"reversal_reason"`
+	BatchID           *string              `json:"batch_id,omitempty" db:"batch_id"`               // Shared by every leg of a batch transfer
+	FeeForID          *int64               `json:"fee_for_id,omitempty" db:"fee_for_id"`           // Transaction a fee was charged for
+	ExchangeForID     *int64               `json:"exchange_for_id,omitempty" db:"exchange_for_id"` // Cross-currency transfer an exchange settles
+	Status            TransactionStatus    `json:"status" db:"status"`
+	BankCode          *string              `json:"bank_code,omitempty" db:"bank_code"`                     // Withdrawals only
+	BankAccountNumber *string              `json:"bank_account_number,omitempty" db:"bank_account_number"` // Withdrawals only
+	BankAccountName   *string              `json:"bank_account_name,omitempty" db:"bank_account_name"`     // Withdrawals only
+	ExternalReference *string              `json:"external_reference,omitempty" db:"external_reference"`   // Payout provider reference
+	FailureReason     *string              `json:"failure_reason,omitempty" db:"failure_reason"`
+	Description       *string              `json:"description,omitempty" db:"description"`
+	ClientReference   *string              `json:"client_reference,omitempty" db:"client_reference"` // Sender's own reference, unique per sending user
+	ReferenceUserID   *int64               `json:"-" db:"reference_user_id"`                         // Sending user the client reference is unique for
+	Metadata          Metadata             `json:"metadata,omitempty" db:"metadata" swaggertype:"object"`
+	Direction         TransactionDirection `json:"direction,omitempty" db:"-"` // Relative to the wallet history is read for
+	Fee               *Money               `json:"fee,omitempty" db:"-"`       // Fee charged with the transaction, set when it is created
+	CreatedAt         time.Time            `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt         time.Time            `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// DirectionFor returns whether the transaction is incoming or outgoing for the given wallets
+func (t *Transaction) DirectionFor(walletIDs ...int64) TransactionDirection {
+	for _, id := range walletIDs {
+		if t.SenderWalletID != nil && *t.SenderWalletID == id {
+			return TransactionDirectionOut
+		}
+	}
+	return TransactionDirectionIn
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (w *Wallet) ApplyCurrency() {
+	w.Balance.Currency = w.Currency
+	w.HeldBalance.Currency = w.Currency
+	w.AvailableBalance = w.Available()
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (t *Transaction) ApplyCurrency() {
+	t.Amount.Currency = t.Currency
+	if t.CounterCurrency != nil {
+		if t.CounterAmount != nil {
+			t.CounterAmount.Currency = *t.CounterCurrency
+		}
+		if t.FxSpread != nil {
+			t.FxSpread.Currency = *t.CounterCurrency
+		}
+	}
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (e *LedgerEntry) ApplyCurrency() {
+	e.Amount.Currency = e.Currency
+	if e.BalanceAfter != nil {
+		e.BalanceAfter.Currency = e.Currency
+	}
+}
+
+// LedgerDirection is the side of a ledger posting
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+// Ledger accounts that live outside of the wallets table
+const (
+	LedgerAccountTopUp          = "external:topup" // Top-ups booked before system wallets existed
+	LedgerAccountOpeningBalance = "external:opening_balance"
+	LedgerAccountPayout         = "external:payout"
+	LedgerAccountWithdrawalHold = "internal:withdrawal_hold" // Withdrawals held before system wallets existed
+	LedgerAccountFXPosition     = "internal:fx_position"     // Cross-currency transfers posted before FX position wallets existed
+	LedgerAccountSuspense       = "internal:suspense"        // Counterpart of ledger-only reconciliation fixes
+)
+
+// LedgerEntry is one side of a double-entry posting. Wallet accounts are liabilities,
+// so a credit increases the wallet balance and a debit decreases it.
+type LedgerEntry struct {
+	ID            int64           `json:"id" db:"id" goqu:"skipinsert"`
+	JournalID     string          `json:"journal_id" db:"journal_id"`
+	TransactionID *int64          `json:"transaction_id" db:"transaction_id"`
+	WalletID      *int64          `json:"wallet_id" db:"wallet_id"` // Nullable for external accounts
+	Account       string          `json:"account" db:"account"`
+	Direction     LedgerDirection `json:"direction" db:"direction"`
+	Amount        Money           `json:"amount" db:"amount"`
+	Currency      string          `json:"currency" db:"currency"`
+	BalanceAfter  *Money          `json:"balance_after" db:"balance_after"` // Wallet balance snapshot after this posting
+	CreatedAt     time.Time       `json:"created_at" db:"created_at" goqu:"skipinsert"`
+}
+
+// WalletLedgerAccount returns the ledger account name of a wallet
+func WalletLedgerAccount(walletID int64) string {
+	return fmt.Sprintf("wallet:%d", walletID)
+}
+
+// ValidateJournal checks that the entries of a journal balance, per currency
+func ValidateJournal(entries []LedgerEntry) error {
+	if len(entries) < 2 {
+		return ErrUnbalancedJournal
+	}
+	totals := make(map[string]int64)
+	for _, e := range entries {
+		if !e.Amount.IsPositive() {
+			return fmt.Errorf("%w: non-positive posting on %s", ErrUnbalancedJournal, e.Account)
+		}
+		switch e.Direction {
+		case LedgerDebit:
+			totals[e.Amount.Currency] -= e.Amount.MinorUnits
+		case LedgerCredit:
+			totals[e.Amount.Currency] += e.Amount.MinorUnits
+		default:
+			return fmt.Errorf("%w: unknown direction %q", ErrUnbalancedJournal, e.Direction)
+		}
+	}
+	for currency, total := range totals {
+		if total != 0 {
+			return fmt.Errorf("%w: %s off by %d minor units", ErrUnbalancedJournal, currency, total)
+		}
+	}
+	return nil
+}
+
+// BankAccount is an external destination for withdrawals
+type BankAccount struct {
+	BankCode      string `json:"bank_code" validate:"required"`
+	AccountNumber string `json:"account_number" validate:"required"`
+	AccountName   string `json:"account_name" validate:"required"`
+}
+
+// PayoutRequest asks a payout provider to send money to a bank account
+type PayoutRequest struct {
+	TransactionID int64
+	Amount        Money
+	Account       BankAccount
+}
+
+// PayoutResult is the provider outcome. Status is pending when the provider settles asynchronously.
+type PayoutResult struct {
+	Status        TransactionStatus
+	Reference     string
+	FailureReason string
+}
+
+// PayoutProvider sends withdrawals out of the system
+type PayoutProvider interface {
+	Payout(ctx context.Context, req PayoutRequest) (*PayoutResult, error)
+}
+
+// FXRate is the locally stored mid rate of a currency pair
+type FXRate struct {
+	ID            int64     `json:"id" db:"id" goqu:"skipinsert"`
+	BaseCurrency  string    `json:"base_currency" db:"base_currency" example:"USD"`
+	QuoteCurrency string    `json:"quote_currency" db:"quote_currency" example:"IDR"`
+	Rate          Rate      `json:"rate" db:"rate" swaggertype:"string" example:"15650.00000000"` // Units of quote currency per unit of base currency
+	SpreadBps     int       `json:"spread_bps" db:"spread_bps" example:"50"`                      // Basis points taken off the mid rate
+	CreatedAt     time.Time `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// FXQuote is a time-limited offer to convert an amount at a fixed rate
+type FXQuote struct {
+	ID             string     `json:"id" db:"id"`
+	UserID         int64      `json:"user_id" db:"user_id"`
+	SourceCurrency string     `json:"source_currency" db:"source_currency"`
+	TargetCurrency string     `json:"target_currency" db:"target_currency"`
+	SourceAmount   Money      `json:"source_amount" db:"source_amount"`
+	TargetAmount   Money      `json:"target_amount" db:"target_amount"`
+	MidRate        Rate       `json:"mid_rate" db:"mid_rate" swaggertype:"string"`
+	Rate           Rate       `json:"rate" db:"rate" swaggertype:"string"` // Rate applied after the spread
+	SpreadBps      int        `json:"spread_bps" db:"spread_bps"`
+	Spread         Money      `json:"spread" db:"spread"` // In the target currency
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt         *time.Time `json:"used_at" db:"used_at"`
+	TransactionID  *int64     `json:"transaction_id" db:"transaction_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency columns into the scanned amounts
+func (q *FXQuote) ApplyCurrency() {
+	q.SourceAmount.Currency = q.SourceCurrency
+	q.TargetAmount.Currency = q.TargetCurrency
+	q.Spread.Currency = q.TargetCurrency
+}
+
+// TransferLeg is one recipient of a batch transfer
+type TransferLeg struct {
+	ReceiverUserID int64 `json:"receiver_user_id"`
+	Amount         Money `json:"amount"`
+}
+
+// BatchTransfer is the result of a batch transfer, applied in full or not at all
+type BatchTransfer struct {
+	BatchID      string        `json:"batch_id"`
+	Total        Money         `json:"total"`
+	TotalFee     Money         `json:"total_fee"` // Transfer fees charged on top of Total
+	Transactions []Transaction `json:"transactions"`
+}
+
+// HoldStatus is the lifecycle state of an authorization hold
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// Hold reserves funds in a payer wallet for a merchant until it is captured, voided or expires.
+// Held funds stay in the wallet balance but cannot be spent.
+type Hold struct {
+	ID             int64      `json:"id" db:"id" goqu:"skipinsert"`
+	WalletID       int64      `json:"wallet_id" db:"wallet_id"`
+	MerchantUserID int64      `json:"merchant_user_id" db:"merchant_user_id"`
+	Amount         Money      `json:"amount" db:"amount"`
+	CapturedAmount *Money     `json:"captured_amount,omitempty" db:"captured_amount"`
+	Currency       string     `json:"currency" db:"currency"`
+	Status         HoldStatus `json:"status" db:"status"`
+	TransactionID  *int64     `json:"transaction_id,omitempty" db:"transaction_id"` // Capture transaction
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (h *Hold) ApplyCurrency() {
+	h.Amount.Currency = h.Currency
+	if h.CapturedAmount != nil {
+		h.CapturedAmount.Currency = h.Currency
+	}
+}
+
+// ScheduleFrequency is how often a scheduled transfer repeats
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly" // Same day of month as StartAt, clamped to the month end
+)
+
+// ScheduleStatus is the lifecycle state of a scheduled transfer
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "active"
+	ScheduleStatusPaused    ScheduleStatus = "paused" // By the user or after repeated failures
+	ScheduleStatusCompleted ScheduleStatus = "completed"
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+)
+
+// ScheduledTransfer sends a fixed amount to another user on a schedule anchored at StartAt
+type ScheduledTransfer struct {
+	ID                  int64             `json:"id" db:"id" goqu:"skipinsert"`
+	UserID              int64             `json:"user_id" db:"user_id"`
+	ReceiverUserID      int64             `json:"receiver_user_id" db:"receiver_user_id"`
+	Amount              Money             `json:"amount" db:"amount"`
+	Currency            string            `json:"currency" db:"currency"`
+	Frequency           ScheduleFrequency `json:"frequency" db:"frequency"`
+	StartAt             time.Time         `json:"start_at" db:"start_at"`
+	EndAt               *time.Time        `json:"end_at,omitempty" db:"end_at"`
+	NextRunAt           time.Time         `json:"next_run_at" db:"next_run_at"`
+	Occurrences         int               `json:"occurrences" db:"occurrences"`                   // Occurrences already run or skipped
+	Attempts            int               `json:"attempts" db:"attempts"`                         // Failed attempts of the current occurrence
+	ConsecutiveFailures int               `json:"consecutive_failures" db:"consecutive_failures"` // Occurrences failed in a row
+	LastError           *string           `json:"last_error,omitempty" db:"last_error"`
+	Status              ScheduleStatus    `json:"status" db:"status"`
+	CreatedAt           time.Time         `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt           time.Time         `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (s *ScheduledTransfer) ApplyCurrency() {
+	s.Amount.Currency = s.Currency
+}
+
+// ScheduleExecution records one attempt to run a scheduled transfer
+type ScheduleExecution struct {
+	ID            int64             `json:"id" db:"id" goqu:"skipinsert"`
+	ScheduleID    int64             `json:"schedule_id" db:"schedule_id"`
+	OccurrenceAt  time.Time         `json:"occurrence_at" db:"occurrence_at"` // When the occurrence was due
+	Attempt       int               `json:"attempt" db:"attempt"`
+	Status        TransactionStatus `json:"status" db:"status"`
+	TransactionID *int64            `json:"transaction_id,omitempty" db:"transaction_id"`
+	Error         *string           `json:"error,omitempty" db:"error"`
+	ExecutedAt    time.Time         `json:"executed_at" db:"executed_at"`
+}
+
+// PaymentRequestStatus is the lifecycle state of a payment request
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusPending   PaymentRequestStatus = "pending"
+	PaymentRequestStatusAccepted  PaymentRequestStatus = "accepted"
+	PaymentRequestStatusDeclined  PaymentRequestStatus = "declined"
+	PaymentRequestStatusCancelled PaymentRequestStatus = "cancelled" // Withdrawn by the requester
+	PaymentRequestStatusExpired   PaymentRequestStatus = "expired"
+)
+
+// PaymentRequest asks a payer to send money to the requester
+type PaymentRequest struct {
+	ID              int64                `json:"id" db:"id" goqu:"skipinsert"`
+	RequesterUserID int64                `json:"requester_user_id" db:"requester_user_id"`
+	PayerUserID     int64                `json:"payer_user_id" db:"payer_user_id"`
+	Amount          Money                `json:"amount" db:"amount"`
+	Currency        string               `json:"currency" db:"currency"`
+	Note            string               `json:"note" db:"note"`
+	Status          PaymentRequestStatus `json:"status" db:"status"`
+	TransactionID   *int64               `json:"transaction_id,omitempty" db:"transaction_id"` // Transfer made on accept
+	ExpiresAt       time.Time            `json:"expires_at" db:"expires_at"`
+	RespondedAt     *time.Time           `json:"responded_at,omitempty" db:"responded_at"`
+	CreatedAt       time.Time            `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt       time.Time            `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (r *PaymentRequest) ApplyCurrency() {
+	r.Amount.Currency = r.Currency
+}
+
+// ApplyExpiry reports a pending request past its expiry as expired. Requests are not swept,
+// they expire the moment ExpiresAt passes.
+func (r *PaymentRequest) ApplyExpiry(now time.Time) {
+	if r.Status == PaymentRequestStatusPending && !now.Before(r.ExpiresAt) {
+		r.Status = PaymentRequestStatusExpired
+	}
+}
+
+// QRCodeType tells whether a QR code can be paid many times with any amount or once with a fixed one
+type QRCodeType string
+
+const (
+	QRCodeTypeStatic  QRCodeType = "static"  // Reusable, the payer enters the amount
+	QRCodeTypeDynamic QRCodeType = "dynamic" // One payment of a fixed amount before it expires
+)
+
+// QRCodeStatus defines possible statuses of a QR code
+type QRCodeStatus string
+
+const (
+	QRCodeStatusActive  QRCodeStatus = "active"
+	QRCodeStatusPaid    QRCodeStatus = "paid"    // Dynamic codes only
+	QRCodeStatusExpired QRCodeStatus = "expired" // Dynamic codes only, derived from ExpiresAt
+)
+
+// QRCode is a merchant-presented QR code bound to the wallet that receives its payments. The
+// payload only carries Token, never the wallet or user id.
+type QRCode struct {
+	ID            int64        `json:"id" db:"id" goqu:"skipinsert"`
+	WalletID      int64        `json:"wallet_id" db:"wallet_id"`
+	Type          QRCodeType   `json:"type" db:"type"`
+	Token         string       `json:"-" db:"token"`
+	Amount        *Money       `json:"amount,omitempty" db:"amount"` // Dynamic codes only
+	Currency      string       `json:"currency" db:"currency"`
+	MerchantName  string       `json:"merchant_name" db:"merchant_name"`
+	MerchantCity  string       `json:"merchant_city" db:"merchant_city"`
+	Reference     *string      `json:"reference,omitempty" db:"reference"` // Bill number shown to the payer
+	Payload       string       `json:"payload" db:"payload"`               // EMVCo string to render as a QR image
+	Status        QRCodeStatus `json:"status" db:"status"`
+	TransactionID *int64       `json:"transaction_id,omitempty" db:"transaction_id"` // Payment of a dynamic code
+	ExpiresAt     *time.Time   `json:"expires_at,omitempty" db:"expires_at"`         // Dynamic codes only
+	PaidAt        *time.Time   `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt     time.Time    `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amount
+func (q *QRCode) ApplyCurrency() {
+	if q.Amount != nil {
+		q.Amount.Currency = q.Currency
+	}
+}
+
+// ApplyExpiry reports an active dynamic code past its expiry as expired
+func (q *QRCode) ApplyExpiry(now time.Time) {
+	if q.Status == QRCodeStatusActive && q.ExpiresAt != nil && !now.Before(*q.ExpiresAt) {
+		q.Status = QRCodeStatusExpired
+	}
+}
+
+// FeeOperation is an operation a fee can be charged on
+type FeeOperation string
+
+const (
+	FeeOperationTransfer FeeOperation = "transfer"
+	FeeOperationTopUp    FeeOperation = "topup"
+)
+
+// FeeRule prices an operation in a currency. Rules of the same operation and currency form tiers:
+// the rule with the highest MinAmount not above the amount applies.
+type FeeRule struct {
+	ID         int64        `json:"id" db:"id" goqu:"skipinsert"`
+	Operation  FeeOperation `json:"operation" db:"operation"`
+	Currency   string       `json:"currency" db:"currency"`
+	MinAmount  Money        `json:"min_amount" db:"min_amount"` // Lower bound of the tier
+	FlatFee    Money        `json:"flat_fee" db:"flat_fee"`
+	PercentBps int          `json:"percent_bps" db:"percent_bps"` // Basis points of the amount, added to FlatFee
+	MinFee     *Money       `json:"min_fee,omitempty" db:"min_fee"`
+	MaxFee     *Money       `json:"max_fee,omitempty" db:"max_fee"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (r *FeeRule) ApplyCurrency() {
+	r.MinAmount.Currency = r.Currency
+	r.FlatFee.Currency = r.Currency
+	for _, m := range []*Money{r.MinFee, r.MaxFee} {
+		if m != nil {
+			m.Currency = r.Currency
+		}
+	}
+}
+
+// FeeQuote shows what an operation will cost before it is confirmed
+type FeeQuote struct {
+	Operation FeeOperation `json:"operation"`
+	Amount    Money        `json:"amount"`
+	Fee       Money        `json:"fee"`
+	Total     Money        `json:"total"` // Debited for a transfer, credited for a top-up
+	RuleID    *int64       `json:"rule_id,omitempty"`
+}
+
+// TransferLimit caps outgoing transfers of a currency. A nil field means no cap. UserID 0 holds
+// the global limits; a user row overrides them field by field.
+type TransferLimit struct {
+	ID                int64     `json:"id" db:"id" goqu:"skipinsert"`
+	UserID            int64     `json:"user_id" db:"user_id"` // 0 for the global limits
+	Currency          string    `json:"currency" db:"currency"`
+	PerTransactionMax *Money    `json:"per_transaction_max,omitempty" db:"per_transaction_max"`
+	DailyAmountMax    *Money    `json:"daily_amount_max,omitempty" db:"daily_amount_max"`
+	DailyCountMax     *int      `json:"daily_count_max,omitempty" db:"daily_count_max"`
+	MonthlyAmountMax  *Money    `json:"monthly_amount_max,omitempty" db:"monthly_amount_max"`
+	MonthlyCountMax   *int      `json:"monthly_count_max,omitempty" db:"monthly_count_max"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at" goqu:"skipinsert"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at" goqu:"skipinsert"`
+}
+
+// ApplyCurrency copies the currency column into the scanned amounts
+func (l *TransferLimit) ApplyCurrency() {
+	for _, m := range []*Money{l.PerTransactionMax, l.DailyAmountMax, l.MonthlyAmountMax} {
+		if m != nil {
+			m.Currency = l.Currency
+		}
+	}
+}
+
+// TransferUsage is the outgoing volume of a user in one currency in the current day and month
+type TransferUsage struct {
+	DailyAmount   Money `json:"daily_amount" db:"daily_amount"`
+	DailyCount    int   `json:"daily_count" db:"daily_count"`
+	MonthlyAmount Money `json:"monthly_amount" db:"monthly_amount"`
+	MonthlyCount  int   `json:"monthly_count" db:"monthly_count"`
+}
+
+// BalanceDrift compares the stored balance of a wallet with the balance replayed from its
+// transactions and the balance summed from its ledger postings
+type BalanceDrift struct {
+	WalletID        int64         `json:"wallet_id"`
+	UserID          *int64        `json:"user_id,omitempty"`
+	SystemCode      *SystemWallet `json:"system_code,omitempty"`
+	Currency        string        `json:"currency"`
+	StoredBalance   Money         `json:"stored_balance"`
+	ReplayedBalance Money         `json:"replayed_balance"`
+	LedgerBalance   Money         `json:"ledger_balance"`
+	AdjustmentID    *int64        `json:"adjustment_id,omitempty"` // Set once a correcting adjustment has been written
+	FixError        *string       `json:"fix_error,omitempty"`     // Why the drift could not be fixed
+}
+
+// TransactionDrift is what wallets.balance holds on top of the replayed transactions
+func (d BalanceDrift) TransactionDrift() Money {
+	return d.StoredBalance.Sub(d.ReplayedBalance)
+}
+
+// LedgerDrift is what wallets.balance holds on top of the ledger postings
+func (d BalanceDrift) LedgerDrift() Money {
+	return d.StoredBalance.Sub(d.LedgerBalance)
+}
+
+// HasDrift reports whether either source disagrees with the stored balance
+func (d BalanceDrift) HasDrift() bool {
+	return !d.TransactionDrift().IsZero() || !d.LedgerDrift().IsZero()
+}
+
+// Statement lists what moved a wallet during a period, from its opening to its closing balance
+type Statement struct {
+	WalletID       int64           `json:"wallet_id"`
+	WalletName     string          `json:"wallet_name"`
+	AccountHolder  string          `json:"account_holder,omitempty"`
+	Currency       string          `json:"currency"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"` // Exclusive
+	OpeningBalance Money           `json:"opening_balance"`
+	TotalIn        Money           `json:"total_in"`
+	TotalOut       Money           `json:"total_out"`
+	ClosingBalance Money           `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// StatementLine is a transaction as it moved the wallet of a statement
+type StatementLine struct {
+	TransactionID int64                `json:"transaction_id"`
+	Date          time.Time            `json:"date"`
+	Type          TransactionType      `json:"type"`
+	Direction     TransactionDirection `json:"direction"`
+	Description   string               `json:"description"`
+	Amount        Money                `json:"amount"`  // Credited on cross-currency transfers in, the counter amount
+	Balance       Money                `json:"balance"` // Running balance after the line
 }
 
 // UserRepository defines methods for interacting with user data
@@ -49,6 +655,8 @@ type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id int64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetByPhone(ctx context.Context, phone string) (*User, error)
+	GetByHandle(ctx context.Context, handle string) (*User, error)
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id int64) error
 }
@@ -58,10 +666,17 @@ type WalletRepository interface {
 	Create(ctx context.Context, wallet *Wallet) error
 	CreateWithTx(ctx context.Context, tx interface{}, wallet *Wallet) error // For transaction support
 	GetByID(ctx context.Context, id int64) (*Wallet, error)
-	GetByUserID(ctx context.Context, userID int64) (*Wallet, error)
-	GetWalletForUpdate(ctx context.Context, tx interface{}, userID int64) (*Wallet, error) // For locking
-	UpdateBalance(ctx context.Context, id int64, amount float64) error                     // Atomic update
-	UpdateBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, newBalance float64) error
+	GetByUserID(ctx context.Context, userID int64) ([]Wallet, error)
+	GetByUserIDAndCurrency(ctx context.Context, userID int64, currency string) (*Wallet, error)             // Primary wallet
+	GetWalletForUpdate(ctx context.Context, tx interface{}, userID int64, currency string) (*Wallet, error) // Primary wallet, for locking
+	GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*Wallet, error)
+	GetSystemWallet(ctx context.Context, tx interface{}, code SystemWallet, currency string) (*Wallet, error)
+	UpdateStatusWithTx(ctx context.Context, tx interface{}, wallet *Wallet) error
+	UpdatePocketWithTx(ctx context.Context, tx interface{}, wallet *Wallet) error // Name and archived_at
+	List(ctx context.Context) ([]Wallet, error)                                   // Every wallet, for batch jobs
+	UpdateHeldBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, held Money) error
+	UpdateBalance(ctx context.Context, id int64, amount Money) error // Atomic update
+	UpdateBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, newBalance Money) error
 	Delete(ctx context.Context, id int64) error
 }
 
@@ -70,8 +685,111 @@ type TransactionRepository interface {
 	Create(ctx context.Context, transaction *Transaction) error
 	CreateWithTx(ctx context.Context, tx interface{}, transaction *Transaction) error
 	GetByID(ctx context.Context, id int64) (*Transaction, error)
-	GetByWalletID(ctx context.Context, walletID int64, limit, offset int) ([]Transaction, error)
+	GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*Transaction, error)
+	GetBySenderReferenceWithTx(ctx context.Context, tx interface{}, senderUserID int64, reference string) (*Transaction, error)
+	GetByWalletIDs(ctx context.Context, walletIDs []int64, filter TransactionFilter, limit, offset int) ([]Transaction, error) // Newest first
+	CountByWalletIDs(ctx context.Context, walletIDs []int64, filter TransactionFilter) (uint64, error)
+	StreamByWalletIDs(ctx context.Context, walletIDs []int64, filter TransactionFilter, fn func(Transaction) error) error                       // GetByWalletIDs without paging
+	GetByWalletIDsAt(ctx context.Context, walletIDs []int64, filter TransactionFilter, cursor *HistoryCursor, limit int) ([]Transaction, error) // Keyset page, newest first; nil cursor starts at the newest
 	UpdateStatus(ctx context.Context, id int64, status TransactionStatus) error
+	UpdateWithTx(ctx context.Context, tx interface{}, transaction *Transaction) error // Status and settlement fields
+	SumReversalsWithTx(ctx context.Context, tx interface{}, originalID int64) (Money, error)
+	OutgoingUsageWithTx(ctx context.Context, tx interface{}, userID int64, currency string, dayStart, monthStart time.Time) (TransferUsage, error) // Every wallet of the user in currency
+	ReplayBalances(ctx context.Context, tx interface{}, walletIDs []int64) (map[int64]Money, error)                                                // Nil tx reads outside a transaction, no ids means every wallet
+	ReplayBalancesBefore(ctx context.Context, walletIDs []int64, before time.Time) (map[int64]Money, error)
+	GetMovementsBetween(ctx context.Context, walletID int64, from, to time.Time) ([]Transaction, error) // Rows reflected in the balance, oldest first
+}
+
+// LedgerRepository defines methods for interacting with ledger postings
+type LedgerRepository interface {
+	CreateWithTx(ctx context.Context, tx interface{}, entries []LedgerEntry) error
+	GetByWalletID(ctx context.Context, walletID int64, limit, offset int) ([]LedgerEntry, error)
+	GetWalletBalance(ctx context.Context, walletID int64) (Money, error) // Sum of credits minus debits
+	GetWalletBalances(ctx context.Context) (map[int64]Money, error)      // GetWalletBalance for every wallet with postings
+}
+
+// HoldRepository defines methods for interacting with authorization holds
+type HoldRepository interface {
+	CreateWithTx(ctx context.Context, tx interface{}, hold *Hold) error
+	GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*Hold, error)
+	UpdateWithTx(ctx context.Context, tx interface{}, hold *Hold) error // Status, captured amount and transaction
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Hold, error)
+}
+
+// ScheduleRepository defines methods for interacting with scheduled transfers
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *ScheduledTransfer) error
+	GetByID(ctx context.Context, id int64) (*ScheduledTransfer, error)
+	GetByUserID(ctx context.Context, userID int64) ([]ScheduledTransfer, error)
+	Update(ctx context.Context, schedule *ScheduledTransfer) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error)
+	Claim(ctx context.Context, id int64, nextRunAt, leaseUntil time.Time) (bool, error)                                   // Moves next_run_at only if nobody else did
+	GetClaimedForUpdate(ctx context.Context, tx interface{}, id int64, leaseUntil time.Time) (*ScheduledTransfer, error)  // Nil once the claim is gone
+	RecordRunWithTx(ctx context.Context, tx interface{}, schedule *ScheduledTransfer, leaseUntil time.Time) (bool, error) // Only while still claimed
+	CreateExecution(ctx context.Context, execution *ScheduleExecution) error
+	CreateExecutionWithTx(ctx context.Context, tx interface{}, execution *ScheduleExecution) error
+	GetExecutions(ctx context.Context, scheduleID int64, limit int) ([]ScheduleExecution, error)
+}
+
+// PaymentRequestRepository defines methods for interacting with payment requests
+type PaymentRequestRepository interface {
+	Create(ctx context.Context, request *PaymentRequest) error
+	GetByID(ctx context.Context, id int64) (*PaymentRequest, error)
+	GetByPayerID(ctx context.Context, payerUserID int64, status PaymentRequestStatus, now time.Time) ([]PaymentRequest, error)         // Empty status means all
+	GetByRequesterID(ctx context.Context, requesterUserID int64, status PaymentRequestStatus, now time.Time) ([]PaymentRequest, error) // Empty status means all
+	TransitionWithTx(ctx context.Context, tx interface{}, id int64, from, to PaymentRequestStatus, now time.Time) (bool, error)        // Leaving pending requires the request not to be expired
+	SetTransactionWithTx(ctx context.Context, tx interface{}, id int64, transactionID int64) error
+}
+
+// IdempotencyKeyRepository defines methods for interacting with idempotency keys
+type IdempotencyKeyRepository interface {
+	Create(ctx context.Context, key *IdempotencyKey) (bool, error) // False when the user already holds the key
+	GetByUserIDAndKey(ctx context.Context, userID int64, key string) (*IdempotencyKey, error)
+	Complete(ctx context.Context, id int64, code int, body []byte) error
+	Delete(ctx context.Context, id int64) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// QRCodeRepository defines methods for interacting with QR code data
+type QRCodeRepository interface {
+	Create(ctx context.Context, code *QRCode) error
+	GetByToken(ctx context.Context, token string) (*QRCode, error)
+	GetByWalletIDs(ctx context.Context, walletIDs []int64, now time.Time) ([]QRCode, error)
+	TransitionWithTx(ctx context.Context, tx interface{}, id int64, from, to QRCodeStatus, now time.Time) (bool, error) // Leaving active requires the code not to be expired
+	SetTransactionWithTx(ctx context.Context, tx interface{}, id int64, transactionID int64) error
+}
+
+// WalletStatusEventRepository defines methods for interacting with wallet status events
+type WalletStatusEventRepository interface {
+	CreateWithTx(ctx context.Context, tx interface{}, event *WalletStatusEvent) error
+	GetByWalletID(ctx context.Context, walletID int64) ([]WalletStatusEvent, error)
+	ListSince(ctx context.Context, afterID int64, limit int) ([]WalletStatusEvent, error) // Oldest first, for polling feeds
+}
+
+// LimitRepository defines methods for interacting with transfer limits
+type LimitRepository interface {
+	Get(ctx context.Context, userID int64, currency string) (*TransferLimit, error) // User 0 for the global limits
+	List(ctx context.Context) ([]TransferLimit, error)
+	Upsert(ctx context.Context, limit *TransferLimit) error
+	Delete(ctx context.Context, userID int64, currency string) error
+}
+
+// FeeRuleRepository defines methods for interacting with fee rules
+type FeeRuleRepository interface {
+	List(ctx context.Context) ([]FeeRule, error)
+	Match(ctx context.Context, operation FeeOperation, amount Money) (*FeeRule, error) // Tier covering amount, nil when free
+	Upsert(ctx context.Context, rule *FeeRule) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// FXRepository defines methods for interacting with exchange rates and quotes
+type FXRepository interface {
+	UpsertRate(ctx context.Context, rate *FXRate) error
+	GetRate(ctx context.Context, baseCurrency, quoteCurrency string) (*FXRate, error)
+	ListRates(ctx context.Context) ([]FXRate, error)
+	CreateQuote(ctx context.Context, quote *FXQuote) error
+	GetQuoteForUpdate(ctx context.Context, tx interface{}, id string) (*FXQuote, error)
+	MarkQuoteUsedWithTx(ctx context.Context, tx interface{}, id string, transactionID int64, usedAt time.Time) error
 }
 
 // UserService defines business logic for users
@@ -79,12 +797,120 @@ type UserService interface {
 	Register(ctx context.Context, user *User) error
 	Login(ctx context.Context, email, password string) (string, error) // Returns JWT token
 	GetProfile(ctx context.Context, id int64) (*User, error)
+	UpdateContact(ctx context.Context, id int64, phone, handle *string) (*User, error) // Nil leaves a field as is, empty clears it
+	ResolveRecipient(ctx context.Context, recipient string) (*User, error)             // Email, phone or @handle
+	LookupPayee(ctx context.Context, recipient string) (*Payee, error)
 }
 
 // TransactionService defines business logic for transactions
 type TransactionService interface {
-	TopUp(ctx context.Context, userID int64, amount float64) (*Wallet, error)
-	Transfer(ctx context.Context, senderID, receiverID int64, amount float64) (*Transaction, error)
-	GetHistory(ctx context.Context, userID int64, page, limit int) ([]Transaction, error)
-	GetBalance(ctx context.Context, userID int64) (*Wallet, error)
+	TopUp(ctx context.Context, userID, walletID int64, amount Money) (*Wallet, error)                                                                      // Zero walletID means the primary wallet
+	Transfer(ctx context.Context, senderID, senderWalletID, receiverID int64, amount Money, memo TransferMemo) (*Transaction, error)                       // Zero senderWalletID means the primary wallet
+	TransferToWallet(ctx context.Context, senderID, senderWalletID, receiverWalletID int64, amount Money, memo TransferMemo) (*Transaction, error)         // Pays a given wallet of another user
+	TransferWithTx(ctx context.Context, tx interface{}, senderID, senderWalletID, receiverID int64, amount Money, memo TransferMemo) (*Transaction, error) // Transfer without committing tx
+	TransferToWalletWithTx(ctx context.Context, tx interface{}, senderID, senderWalletID, receiverWalletID int64, amount Money, memo TransferMemo) (*Transaction, error)
+	GetHistory(ctx context.Context, userID int64, filter TransactionFilter, page, limit int) ([]Transaction, *database.Meta, error)
+	GetHistoryPage(ctx context.Context, userID int64, filter TransactionFilter, cursor string, limit int) (*TransactionPage, error) // Empty cursor starts at the newest
+	ExportHistory(ctx context.Context, userID int64, filter TransactionFilter) (TransactionStream, error)                           // Checks the filter and wallets before any row is read
+	GetBalance(ctx context.Context, userID, walletID int64) ([]Wallet, error)                                                       // Every open pocket, or only walletID when set
+	Withdraw(ctx context.Context, userID int64, amount Money, account BankAccount) (*Transaction, error)
+	SettleWithdrawal(ctx context.Context, transactionID int64, reference string) (*Transaction, error)
+	FailWithdrawal(ctx context.Context, transactionID int64, reason string) (*Transaction, error)
+	TransferWithQuote(ctx context.Context, senderID, receiverID int64, quoteID string) (*Transaction, error) // Cross-currency
+	BatchTransfer(ctx context.Context, senderID int64, legs []TransferLeg) (*BatchTransfer, error)           // All legs or none
+	Reverse(ctx context.Context, transactionID int64, amount Money, reason string) (*Transaction, error)     // Zero amount reverses what is left
+	Hold(ctx context.Context, userID, merchantUserID int64, amount Money, expiresIn time.Duration) (*Hold, error)
+	Capture(ctx context.Context, merchantUserID, holdID int64, amount Money) (*Transaction, error) // Zero amount captures the full hold
+	Void(ctx context.Context, merchantUserID, holdID int64) (*Hold, error)
+	ExpireHolds(ctx context.Context, now time.Time) (int, error) // Releases holds past their expiry
+	CreatePocket(ctx context.Context, userID int64, name, currency string) (*Wallet, error)
+	RenamePocket(ctx context.Context, userID, walletID int64, name string) (*Wallet, error)
+	ArchivePocket(ctx context.Context, userID, walletID int64) (*Wallet, error) // The pocket must be empty
+	MoveBetweenPockets(ctx context.Context, userID, fromWalletID, toWalletID int64, amount Money) (*Transaction, error)
+}
+
+// ReconciliationService checks cached wallet balances against transaction history
+type ReconciliationService interface {
+	Report(ctx context.Context) ([]BalanceDrift, error) // One entry per wallet
+	Fix(ctx context.Context, walletID int64) (*BalanceDrift, error)
+}
+
+// ScheduleService defines business logic for scheduled and recurring transfers
+type ScheduleService interface {
+	Create(ctx context.Context, schedule *ScheduledTransfer) error
+	List(ctx context.Context, userID int64) ([]ScheduledTransfer, error)
+	Get(ctx context.Context, userID, id int64) (*ScheduledTransfer, []ScheduleExecution, error)
+	Update(ctx context.Context, userID int64, schedule *ScheduledTransfer) error
+	Cancel(ctx context.Context, userID, id int64) error
+	RunDue(ctx context.Context, now time.Time) (int, error) // Returns the number of schedules processed
+}
+
+// PaymentRequestService defines business logic for requesting money from other users
+type PaymentRequestService interface {
+	Create(ctx context.Context, request *PaymentRequest) error
+	Get(ctx context.Context, userID, id int64) (*PaymentRequest, error) // Visible to both sides
+	ListIncoming(ctx context.Context, payerUserID int64, status PaymentRequestStatus) ([]PaymentRequest, error)
+	ListOutgoing(ctx context.Context, requesterUserID int64, status PaymentRequestStatus) ([]PaymentRequest, error)
+	Accept(ctx context.Context, payerUserID, id int64) (*PaymentRequest, error) // Pays the request with a transfer
+	Decline(ctx context.Context, payerUserID, id int64) (*PaymentRequest, error)
+	Cancel(ctx context.Context, requesterUserID, id int64) (*PaymentRequest, error)
+}
+
+// QRPaymentService defines business logic for merchant-presented QR payments
+type QRPaymentService interface {
+	Generate(ctx context.Context, userID int64, code *QRCode) error // Without an amount the code is static
+	List(ctx context.Context, userID int64) ([]QRCode, error)
+	Pay(ctx context.Context, payerUserID int64, payload string, amount Money) (*Transaction, error) // Zero amount for dynamic codes
+}
+
+// StatementService builds account statements and renders them for download
+type StatementService interface {
+	Generate(ctx context.Context, userID, walletID int64, currency string, month time.Time) (*Statement, error) // Zero walletID means the primary wallet of currency
+	GenerateForWallet(ctx context.Context, walletID int64, month time.Time) (*Statement, error)                 // Any wallet, for compliance
+	WriteCSV(w io.Writer, statement *Statement) error
+	WritePDF(w io.Writer, statement *Statement) error
+}
+
+// IdempotencyService makes retried requests safe by answering them with the stored response
+type IdempotencyService interface {
+	Begin(ctx context.Context, userID int64, key, fingerprint string) (*IdempotencyKey, error) // A completed key is replayed, a processing one belongs to the caller
+	Complete(ctx context.Context, key *IdempotencyKey, code int, body []byte) error
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// WalletStatusService defines business logic for freezing and closing wallets
+type WalletStatusService interface {
+	SetStatus(ctx context.Context, walletID int64, status WalletStatus, reason, actor string) (*Wallet, error)
+	Events(ctx context.Context, walletID int64) ([]WalletStatusEvent, error)
+	Feed(ctx context.Context, afterID int64, limit int) ([]WalletStatusEvent, error)
+}
+
+// LimitService defines business logic for configuring transfer limits
+type LimitService interface {
+	List(ctx context.Context) ([]TransferLimit, error)
+	Effective(ctx context.Context, userID int64, currency string) (*TransferLimit, error) // Global limits merged with the user override
+	Set(ctx context.Context, limit *TransferLimit) error
+	Delete(ctx context.Context, userID int64, currency string) error
+}
+
+// FeeCalculator prices operations from the fee rules
+type FeeCalculator interface {
+	Calculate(ctx context.Context, operation FeeOperation, amount Money) (Money, error)
+}
+
+// FeeService defines business logic for fee rules and quotes
+type FeeService interface {
+	FeeCalculator
+	Quote(ctx context.Context, operation FeeOperation, amount Money) (*FeeQuote, error)
+	ListRules(ctx context.Context) ([]FeeRule, error)
+	SetRule(ctx context.Context, rule *FeeRule) error
+	DeleteRule(ctx context.Context, id int64) error
+}
+
+// FXService defines business logic for exchange rates and quotes
+type FXService interface {
+	ListRates(ctx context.Context) ([]FXRate, error)
+	SetRate(ctx context.Context, rate *FXRate) error
+	ImportRatesCSV(ctx context.Context, r io.Reader) (int, error) // Returns the number of rates imported
+	CreateQuote(ctx context.Context, userID int64, source Money, targetCurrency string) (*FXQuote, error)
 }
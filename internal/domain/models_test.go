@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func posting(account string, direction LedgerDirection, minor int64, currency string) LedgerEntry {
+	return LedgerEntry{Account: account, Direction: direction, Amount: NewMoney(minor, currency)}
+}
+
+func TestValidateJournal(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []LedgerEntry
+		wantErr bool
+	}{
+		{
+			name: "balanced transfer",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 5000, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 5000, "IDR"),
+			},
+		},
+		{
+			name: "one debit split over two credits",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 5000, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 4500, "IDR"),
+				posting(WalletLedgerAccount(3), LedgerCredit, 500, "IDR"),
+			},
+		},
+		{
+			name: "balanced in each currency",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 100, "USD"),
+				posting(WalletLedgerAccount(3), LedgerCredit, 100, "USD"),
+				posting(WalletLedgerAccount(4), LedgerDebit, 1565000, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 1565000, "IDR"),
+			},
+		},
+		{
+			name:    "no entries",
+			wantErr: true,
+		},
+		{
+			name: "single entry",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 5000, "IDR"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "debits exceed credits",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 5000, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 4999, "IDR"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "balanced only across currencies",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 100, "USD"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 100, "IDR"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero posting",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, 0, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 0, "IDR"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative posting",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDebit, -100, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, -100, "IDR"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown direction",
+			entries: []LedgerEntry{
+				posting(WalletLedgerAccount(1), LedgerDirection("sideways"), 100, "IDR"),
+				posting(WalletLedgerAccount(2), LedgerCredit, 100, "IDR"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJournal(tt.entries)
+			if tt.wantErr && !errors.Is(err, ErrUnbalancedJournal) {
+				t.Errorf("ValidateJournal() error = %v, want ErrUnbalancedJournal", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateJournal() error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used when an amount arrives without an explicit currency
+const DefaultCurrency = "IDR"
+
+// MoneyScale is the number of decimal places stored for every amount, matching DECIMAL(15, 2)
+const MoneyScale = 2
+
+const minorUnitsPerMajor = 100
+
+// MaxMoneyMinorUnits is the largest amount DECIMAL(15, 2) holds, 9999999999999.99, in minor units
+const MaxMoneyMinorUnits = 999_999_999_999_999
+
+var (
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrAmountPrecision = errors.New("amount must not have more than two decimal places")
+)
+
+// Money is an exact monetary amount expressed in minor units (e.g. cents) of a currency.
+// It must be used instead of float64 for balances and amounts so arithmetic never rounds.
+type Money struct {
+	MinorUnits int64  `json:"amount" swaggertype:"string" example:"15000.00"` // encoded as a decimal string, see MarshalJSON
+	Currency   string `json:"currency" example:"IDR"`
+}
+
+// NewMoney builds a Money value from minor units
+func NewMoney(minorUnits int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{MinorUnits: minorUnits, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "1500", "1500.5" or "1500.50".
+// Amounts with more than two significant decimal places, exponents, fractions and amounts
+// outside the range of DECIMAL(15, 2) are rejected.
+func ParseMoney(value, currency string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Money{}, ErrInvalidAmount
+	}
+	if !isPlainDecimal(value) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	r.Mul(r, big.NewRat(minorUnitsPerMajor, 1))
+	if !r.IsInt() {
+		return Money{}, ErrAmountPrecision
+	}
+	minor := r.Num()
+	if !minor.IsInt64() || minor.Int64() > MaxMoneyMinorUnits || minor.Int64() < -MaxMoneyMinorUnits {
+		return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, value)
+	}
+	return NewMoney(minor.Int64(), currency), nil
+}
+
+// isPlainDecimal reports whether value is an optionally negative run of digits with an optional
+// fractional part, such as "-1500.50". big.Rat alone would also take "1/2", "1e9" or "0x10".
+func isPlainDecimal(value string) bool {
+	if strings.HasPrefix(value, "-") {
+		value = value[1:]
+	}
+	integer, fraction, hasPoint := strings.Cut(value, ".")
+	if integer == "" || (hasPoint && fraction == "") {
+		return false
+	}
+	for _, part := range []string{integer, fraction} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MustParseMoney is like ParseMoney but panics on error. Intended for constants only.
+func MustParseMoney(value, currency string) Money {
+	m, err := ParseMoney(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// String formats the amount as a plain decimal with two places, e.g. "1500.50"
+func (m Money) String() string {
+	minor := m.MinorUnits
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+	// Work on uint64 so math.MinInt64 does not overflow when negated
+	abs := uint64(minor)
+	if minor < 0 {
+		abs = uint64(-(minor + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/minorUnitsPerMajor, abs%minorUnitsPerMajor)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool { return m.MinorUnits == 0 }
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool { return m.MinorUnits > 0 }
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool { return m.MinorUnits < 0 }
+
+// Add returns m + o. Both values are expected to share a currency.
+func (m Money) Add(o Money) Money {
+	return Money{MinorUnits: m.MinorUnits + o.MinorUnits, Currency: m.currencyOr(o)}
+}
+
+// Sub returns m - o. Both values are expected to share a currency.
+func (m Money) Sub(o Money) Money {
+	return Money{MinorUnits: m.MinorUnits - o.MinorUnits, Currency: m.currencyOr(o)}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{MinorUnits: -m.MinorUnits, Currency: m.Currency}
+}
+
+// Cmp compares m and o and returns -1, 0 or +1
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.MinorUnits < o.MinorUnits:
+		return -1
+	case m.MinorUnits > o.MinorUnits:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// LessThan reports whether m < o
+func (m Money) LessThan(o Money) bool { return m.Cmp(o) < 0 }
+
+// SameCurrency returns a *CurrencyMismatchError when o is not in the currency of m
+func (m Money) SameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return &CurrencyMismatchError{SourceCurrency: o.Currency, TargetCurrency: m.Currency}
+	}
+	return nil
+}
+
+// NormalizeCurrency upper-cases an ISO-4217 code, defaulting to DefaultCurrency when empty
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+		}
+	}
+	return code, nil
+}
+
+func (m Money) currencyOr(o Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	return o.Currency
+}
+
+// moneyJSON is the wire representation. The amount is a decimal string so clients never
+// have to go through binary floating point.
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes Money as {"amount": "1500.50", "currency": "IDR"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: currency})
+}
+
+// UnmarshalJSON accepts a JSON number (1500.5), a decimal string ("1500.50") or
+// an object ({"amount": "1500.50", "currency": "IDR"}). The number is parsed from
+// its literal text, never through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	currency := m.Currency
+	var raw json.RawMessage = data
+	if data[0] == '{' {
+		var obj struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		raw = obj.Amount
+		if obj.Currency != "" {
+			currency = strings.ToUpper(obj.Currency)
+		}
+	}
+
+	text, err := amountLiteral(raw)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(text, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func amountLiteral(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "", ErrInvalidAmount
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var n json.Number
+	if err := dec.Decode(&n); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAmount, string(raw))
+	}
+	return n.String(), nil
+}
+
+// Value implements driver.Valuer. Amounts are written as exact decimal strings.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (m *Money) Scan(src interface{}) error {
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	var text string
+	switch v := src.(type) {
+	case nil:
+		*m = NewMoney(0, currency)
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: %v", ErrInvalidAmount, v)
+		}
+		text = strconv.FormatFloat(v, 'f', MoneyScale, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	parsed, err := ParseMoney(text, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value string
+		minor int64
+	}{
+		{"1500", 150000},
+		{"1500.5", 150050},
+		{"1500.50", 150050},
+		{"1500.500", 150050},
+		{" 0.01 ", 1},
+		{"-25.10", -2510},
+		{"0", 0},
+		{"9999999999999.99", MaxMoneyMinorUnits},
+		{"-9999999999999.99", -MaxMoneyMinorUnits},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value, "usd")
+		if err != nil {
+			t.Errorf("ParseMoney(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got.MinorUnits != tt.minor || got.Currency != "usd" {
+			t.Errorf("ParseMoney(%q) = %+v, want %d minor units", tt.value, got, tt.minor)
+		}
+	}
+}
+
+func TestParseMoneyRejects(t *testing.T) {
+	tests := []struct {
+		value string
+		err   error
+	}{
+		{"", ErrInvalidAmount},
+		{"abc", ErrInvalidAmount},
+		{"1/2", ErrInvalidAmount},
+		{"1e9", ErrInvalidAmount},
+		{"1E2", ErrInvalidAmount},
+		{"0x10", ErrInvalidAmount},
+		{"+5", ErrInvalidAmount},
+		{".5", ErrInvalidAmount},
+		{"5.", ErrInvalidAmount},
+		{"1,000", ErrInvalidAmount},
+		{"--1", ErrInvalidAmount},
+		{"10000000000000.00", ErrInvalidAmount},
+		{"-10000000000000", ErrInvalidAmount},
+		{"99999999999999999999999", ErrInvalidAmount},
+		{"1.005", ErrAmountPrecision},
+	}
+	for _, tt := range tests {
+		if _, err := ParseMoney(tt.value, "IDR"); !errors.Is(err, tt.err) {
+			t.Errorf("ParseMoney(%q) error = %v, want %v", tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestNewMoneyDefaultsCurrency(t *testing.T) {
+	if got := NewMoney(100, ""); got.Currency != DefaultCurrency {
+		t.Errorf("NewMoney currency = %q, want %q", got.Currency, DefaultCurrency)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{150050, "1500.50"},
+		{-2510, "-25.10"},
+		{-9223372036854775808, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := NewMoney(tt.minor, "IDR").String(); got != tt.want {
+			t.Errorf("NewMoney(%d).String() = %q, want %q", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		minor    int64
+		currency string
+	}{
+		{`1500.5`, 150050, "IDR"},
+		{`"1500.50"`, 150050, "IDR"},
+		{`{"amount": "12.34", "currency": "usd"}`, 1234, "USD"},
+		{`{"amount": 0.1}`, 10, "IDR"},
+	}
+	for _, tt := range tests {
+		m := Money{Currency: "IDR"}
+		if err := json.Unmarshal([]byte(tt.input), &m); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.input, err)
+			continue
+		}
+		if m.MinorUnits != tt.minor || m.Currency != tt.currency {
+			t.Errorf("Unmarshal(%s) = %+v, want %d %s", tt.input, m, tt.minor, tt.currency)
+		}
+	}
+
+	for _, input := range []string{`1e3`, `"1/2"`, `true`, `{"amount": "1.001"}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(input), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v, want an error", input, m)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		src   interface{}
+		minor int64
+	}{
+		{[]byte("1500.50"), 150050},
+		{"-3.00", -300},
+		{int64(42), 4200},
+		{float64(0.1), 10},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		m := Money{Currency: "USD"}
+		if err := m.Scan(tt.src); err != nil {
+			t.Errorf("Scan(%v) returned error: %v", tt.src, err)
+			continue
+		}
+		if m.MinorUnits != tt.minor || m.Currency != "USD" {
+			t.Errorf("Scan(%v) = %+v, want %d USD", tt.src, m, tt.minor)
+		}
+	}
+}
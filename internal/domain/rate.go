@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places stored for exchange rates, matching DECIMAL(20, 8)
+const RateScale = 8
+
+var rateDenominator = big.NewInt(100_000_000)
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exact exchange rate with eight decimal places. It tells how many units of
+// the quote currency one unit of the base currency buys.
+type Rate struct {
+	scaled int64
+}
+
+// ParseRate parses a decimal string such as "15650.25" or "0.0000639"
+func ParseRate(value string) (Rate, error) {
+	value = strings.TrimSpace(value)
+	if !isPlainDecimal(value) {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || r.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	r.Mul(r, new(big.Rat).SetInt(rateDenominator))
+	if !r.IsInt() {
+		return Rate{}, fmt.Errorf("%w: more than %d decimal places", ErrInvalidRate, RateScale)
+	}
+	if !r.Num().IsInt64() {
+		return Rate{}, fmt.Errorf("%w: %q is out of range", ErrInvalidRate, value)
+	}
+	return Rate{scaled: r.Num().Int64()}, nil
+}
+
+func (r Rate) rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(r.scaled), rateDenominator)
+}
+
+func rateFromRat(v *big.Rat) Rate {
+	scaled := new(big.Rat).Mul(v, new(big.Rat).SetInt(rateDenominator))
+	// Truncate to eight decimals
+	q := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+	return Rate{scaled: q.Int64()}
+}
+
+// IsZero reports whether the rate is unset
+func (r Rate) IsZero() bool { return r.scaled == 0 }
+
+// Inverse returns 1/r, truncated to eight decimals. It is for display; convert with
+// ConvertInverse.
+func (r Rate) Inverse() Rate {
+	if r.scaled == 0 {
+		return Rate{}
+	}
+	return rateFromRat(new(big.Rat).Inv(r.rat()))
+}
+
+// WithSpread lowers the rate by spreadBps basis points, which is what the customer gets
+func (r Rate) WithSpread(spreadBps int) Rate {
+	factor := big.NewRat(int64(10_000-spreadBps), 10_000)
+	return rateFromRat(new(big.Rat).Mul(r.rat(), factor))
+}
+
+// Convert converts amount into targetCurrency, rounding down to the minor unit
+func (r Rate) Convert(amount Money, targetCurrency string) (Money, error) {
+	return convert(amount, targetCurrency, big.NewInt(r.scaled), rateDenominator)
+}
+
+// ConvertInverse converts amount into targetCurrency at 1/r less spreadBps basis points,
+// rounding down to the minor unit. It divides by r rather than converting at Inverse, whose
+// eight decimals would shave a fraction of a basis point off every conversion.
+func (r Rate) ConvertInverse(amount Money, targetCurrency string, spreadBps int) (Money, error) {
+	if r.scaled == 0 {
+		return Money{}, ErrInvalidRate
+	}
+	numerator := new(big.Int).Mul(rateDenominator, big.NewInt(int64(10_000-spreadBps)))
+	denominator := new(big.Int).Mul(big.NewInt(r.scaled), big.NewInt(10_000))
+	return convert(amount, targetCurrency, numerator, denominator)
+}
+
+// convert multiplies amount by numerator/denominator, rounding down, and rejects results
+// that do not fit DECIMAL(15, 2)
+func convert(amount Money, targetCurrency string, numerator, denominator *big.Int) (Money, error) {
+	v := new(big.Int).Mul(big.NewInt(amount.MinorUnits), numerator)
+	v.Quo(v, denominator)
+	if !v.IsInt64() || v.Int64() > MaxMoneyMinorUnits || v.Int64() < -MaxMoneyMinorUnits {
+		return Money{}, fmt.Errorf("%w: converted amount is out of range", ErrInvalidAmount)
+	}
+	return NewMoney(v.Int64(), targetCurrency), nil
+}
+
+// String formats the rate with eight decimal places
+func (r Rate) String() string {
+	return r.rat().FloatString(RateScale)
+}
+
+// MarshalJSON encodes the rate as a decimal string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number, parsed from its literal text
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text, err := amountLiteral(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRate, string(data))
+	}
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (r *Rate) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	case float64:
+		text = strconv.FormatFloat(v, 'f', RateScale, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", src)
+	}
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
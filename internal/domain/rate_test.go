@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"15650.25", "15650.25000000"},
+		{"0.0000639", "0.00006390"},
+		{" 1 ", "1.00000000"},
+		{"0.00000001", "0.00000001"},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.value)
+		if err != nil {
+			t.Errorf("ParseRate(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseRate(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseRateRejects(t *testing.T) {
+	for _, value := range []string{"", "0", "0.00", "-1.5", "1/3", "1e5", "abc", "0.000000001", "99999999999999999999"} {
+		if _, err := ParseRate(value); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("ParseRate(%q) error = %v, want ErrInvalidRate", value, err)
+		}
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	rate, err := ParseRate("15650.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := rate.Convert(MustParseMoney("10.00", "USD"), "IDR")
+	if err != nil || got.String() != "156502.50" || got.Currency != "IDR" {
+		t.Errorf("Convert = %s %s, %v; want 156502.50 IDR", got, got.Currency, err)
+	}
+
+	// Converting rounds down to the minor unit
+	inverse, err := ParseRate("0.00006389")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = inverse.Convert(MustParseMoney("100000.00", "IDR"), "USD")
+	if err != nil || got.String() != "6.38" {
+		t.Errorf("Convert = %s, %v; want 6.38", got, err)
+	}
+
+	// A product beyond DECIMAL(15, 2) is rejected rather than wrapped
+	huge, err := ParseRate("90000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := huge.Convert(MustParseMoney("9999999999999.99", "USD"), "IDR"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Convert out of range error = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := huge.Convert(MustParseMoney("1000000", "USD"), "IDR"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Convert beyond the money range error = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestRateConvertInverse(t *testing.T) {
+	rate, err := ParseRate("15650")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		amount    string
+		spreadBps int
+		want      string
+	}{
+		// 10,000,000 / 15650 = 638.977...; the truncated inverse 0.00006389 would give 638.90
+		{"10000000.00", 0, "638.97"},
+		{"10000000.00", 50, "635.78"},
+		{"15650.00", 0, "1.00"},
+		{"156.49", 0, "0.00"},
+	}
+	for _, tt := range tests {
+		got, err := rate.ConvertInverse(MustParseMoney(tt.amount, "IDR"), "USD", tt.spreadBps)
+		if err != nil {
+			t.Errorf("ConvertInverse(%s, %d) error = %v", tt.amount, tt.spreadBps, err)
+			continue
+		}
+		if got.String() != tt.want || got.Currency != "USD" {
+			t.Errorf("ConvertInverse(%s, %d) = %s %s, want %s USD", tt.amount, tt.spreadBps, got, got.Currency, tt.want)
+		}
+	}
+
+	tiny, err := ParseRate("0.00000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tiny.ConvertInverse(MustParseMoney("100000", "IDR"), "USD", 0); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("ConvertInverse out of range error = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := (Rate{}).ConvertInverse(MustParseMoney("1", "IDR"), "USD", 0); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("ConvertInverse at a zero rate error = %v, want ErrInvalidRate", err)
+	}
+}
+
+func TestRateWithSpreadAndInverse(t *testing.T) {
+	rate, err := ParseRate("16000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rate.WithSpread(50).String(); got != "15920.00000000" {
+		t.Errorf("WithSpread(50) = %s, want 15920.00000000", got)
+	}
+	if got := rate.Inverse().String(); got != "0.00006250" {
+		t.Errorf("Inverse() = %s, want 0.00006250", got)
+	}
+	if got := (Rate{}).Inverse(); !got.IsZero() {
+		t.Errorf("Inverse of a zero rate = %s, want zero", got)
+	}
+}
+
+func TestRateJSONAndScan(t *testing.T) {
+	var r Rate
+	if err := json.Unmarshal([]byte(`"1.25"`), &r); err != nil || r.String() != "1.25000000" {
+		t.Errorf("Unmarshal string = %s, %v", r, err)
+	}
+	if err := json.Unmarshal([]byte(`0.5`), &r); err != nil || r.String() != "0.50000000" {
+		t.Errorf("Unmarshal number = %s, %v", r, err)
+	}
+	if err := json.Unmarshal([]byte(`2e3`), &r); err == nil {
+		t.Errorf("Unmarshal(2e3) = %s, want an error", r)
+	}
+	out, err := json.Marshal(r)
+	if err != nil || string(out) != `"0.50000000"` {
+		t.Errorf("Marshal = %s, %v", out, err)
+	}
+
+	if err := r.Scan([]byte("15650.25000000")); err != nil || r.String() != "15650.25000000" {
+		t.Errorf("Scan = %s, %v", r, err)
+	}
+	if err := r.Scan(nil); err != nil || !r.IsZero() {
+		t.Errorf("Scan(nil) = %s, %v", r, err)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPhoneCountryCode replaces the leading 0 of local phone numbers
+const DefaultPhoneCountryCode = "62"
+
+// NormalizePhone turns a phone number into E.164 form, e.g. "0812-3456-7890" into "+6281234567890"
+func NormalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0, r == ' ', r == '-', r == '(', r == ')':
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+		}
+	}
+	number := digits.String()
+	if !strings.HasPrefix(strings.TrimSpace(phone), "+") {
+		if !strings.HasPrefix(number, "0") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+		}
+		number = DefaultPhoneCountryCode + number[1:]
+	}
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+	}
+	return "+" + number, nil
+}
+
+// NormalizeHandle lowercases a public handle and drops its leading @. Handles are 3 to 30
+// letters, digits, dots or underscores.
+func NormalizeHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+	if len(handle) < 3 || len(handle) > 30 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidHandle, handle)
+	}
+	for _, r := range handle {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_') {
+			return "", fmt.Errorf("%w: %q", ErrInvalidHandle, handle)
+		}
+	}
+	return handle, nil
+}
+
+// MaskName keeps the first letter of every word of a name, e.g. "Budi Santoso" becomes
+// "B*** S******", so a sender can recognise a payee without learning their full name
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		runes := []rune(word)
+		for j := 1; j < len(runes); j++ {
+			runes[j] = '*'
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// OccurrenceAt returns when the n-th occurrence (counting from zero) of the schedule is due.
+// Occurrences are anchored at StartAt so monthly schedules do not drift after short months.
+func (s *ScheduledTransfer) OccurrenceAt(n int) time.Time {
+	switch s.Frequency {
+	case ScheduleFrequencyDaily:
+		return s.StartAt.AddDate(0, 0, n)
+	case ScheduleFrequencyWeekly:
+		return s.StartAt.AddDate(0, 0, 7*n)
+	case ScheduleFrequencyMonthly:
+		return addMonthsClamped(s.StartAt, n)
+	default:
+		return s.StartAt
+	}
+}
+
+// Finished reports whether the schedule has no occurrence left to run
+func (s *ScheduledTransfer) Finished() bool {
+	if s.Frequency == ScheduleFrequencyOnce {
+		return s.Occurrences > 0
+	}
+	return s.EndAt != nil && s.OccurrenceAt(s.Occurrences).After(*s.EndAt)
+}
+
+// Advance moves past the current occurrence and any occurrence already missed at now,
+// completing the schedule when nothing is left
+func (s *ScheduledTransfer) Advance(now time.Time) {
+	s.Occurrences++
+	s.SkipMissed(now)
+}
+
+// SkipMissed moves past occurrences that were due before now. Missed occurrences are never
+// run late in bulk.
+func (s *ScheduledTransfer) SkipMissed(now time.Time) {
+	for !s.Finished() && s.OccurrenceAt(s.Occurrences).Before(now) {
+		s.Occurrences++
+	}
+	s.NextRunAt = s.OccurrenceAt(s.Occurrences)
+	if s.Finished() {
+		s.Status = ScheduleStatusCompleted
+	}
+}
+
+// addMonthsClamped adds months to t, using the last day of the month when the day does not exist
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddMonthsClamped(t *testing.T) {
+	tests := []struct {
+		start  time.Time
+		months int
+		want   time.Time
+	}{
+		{time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC), 1, time.Date(2026, 2, 15, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC), 1, time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2028, 1, 31, 9, 30, 0, 0, time.UTC), 1, time.Date(2028, 2, 29, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC), 2, time.Date(2026, 3, 31, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC), 3, time.Date(2026, 4, 30, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 12, 31, 9, 30, 0, 0, time.UTC), 2, time.Date(2027, 2, 28, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 5, 31, 9, 30, 0, 0, time.UTC), 0, time.Date(2026, 5, 31, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := addMonthsClamped(tt.start, tt.months); !got.Equal(tt.want) {
+			t.Errorf("addMonthsClamped(%s, %d) = %s, want %s", tt.start, tt.months, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrenceAt(t *testing.T) {
+	start := time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		frequency ScheduleFrequency
+		n         int
+		want      time.Time
+	}{
+		{ScheduleFrequencyOnce, 3, start},
+		{ScheduleFrequencyDaily, 3, time.Date(2026, 2, 3, 8, 0, 0, 0, time.UTC)},
+		{ScheduleFrequencyWeekly, 2, time.Date(2026, 2, 14, 8, 0, 0, 0, time.UTC)},
+		// Anchored at StartAt, so the short February does not pull March back to the 28th
+		{ScheduleFrequencyMonthly, 1, time.Date(2026, 2, 28, 8, 0, 0, 0, time.UTC)},
+		{ScheduleFrequencyMonthly, 2, time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule := &ScheduledTransfer{Frequency: tt.frequency, StartAt: start}
+		if got := schedule.OccurrenceAt(tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s OccurrenceAt(%d) = %s, want %s", tt.frequency, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleFinished(t *testing.T) {
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	endAt := time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule ScheduledTransfer
+		want     bool
+	}{
+		{"once not run", ScheduledTransfer{Frequency: ScheduleFrequencyOnce, StartAt: start}, false},
+		{"once run", ScheduledTransfer{Frequency: ScheduleFrequencyOnce, StartAt: start, Occurrences: 1}, true},
+		{"daily without end", ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, Occurrences: 1000}, false},
+		{"daily on the end", ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, EndAt: &endAt, Occurrences: 2}, false},
+		{"daily past the end", ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, EndAt: &endAt, Occurrences: 3}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.Finished(); got != tt.want {
+			t.Errorf("%s: Finished() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleSkipMissed(t *testing.T) {
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	endAt := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		schedule    ScheduledTransfer
+		now         time.Time
+		occurrences int
+		nextRunAt   time.Time
+		status      ScheduleStatus
+	}{
+		{
+			name:        "nothing missed",
+			schedule:    ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, Status: ScheduleStatusActive},
+			now:         start.Add(-time.Hour),
+			occurrences: 0,
+			nextRunAt:   start,
+			status:      ScheduleStatusActive,
+		},
+		{
+			name:        "due now is not missed",
+			schedule:    ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, Occurrences: 2, Status: ScheduleStatusActive},
+			now:         time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC),
+			occurrences: 2,
+			nextRunAt:   time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC),
+			status:      ScheduleStatusActive,
+		},
+		{
+			name:        "skips to the next future occurrence",
+			schedule:    ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, Status: ScheduleStatusActive},
+			now:         time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC),
+			occurrences: 5,
+			nextRunAt:   time.Date(2026, 3, 6, 8, 0, 0, 0, time.UTC),
+			status:      ScheduleStatusActive,
+		},
+		{
+			name:        "completes past the end",
+			schedule:    ScheduledTransfer{Frequency: ScheduleFrequencyDaily, StartAt: start, EndAt: &endAt, Status: ScheduleStatusActive},
+			now:         time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC),
+			occurrences: 10,
+			nextRunAt:   time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC),
+			status:      ScheduleStatusCompleted,
+		},
+		{
+			name:        "advanced once schedule completes",
+			schedule:    ScheduledTransfer{Frequency: ScheduleFrequencyOnce, StartAt: start, Occurrences: 1, Status: ScheduleStatusActive},
+			now:         start,
+			occurrences: 1,
+			nextRunAt:   start,
+			status:      ScheduleStatusCompleted,
+		},
+	}
+	for _, tt := range tests {
+		schedule := tt.schedule
+		schedule.SkipMissed(tt.now)
+		if schedule.Occurrences != tt.occurrences || !schedule.NextRunAt.Equal(tt.nextRunAt) || schedule.Status != tt.status {
+			t.Errorf("%s: got %d occurrences, next run %s, %s; want %d, %s, %s", tt.name,
+				schedule.Occurrences, schedule.NextRunAt, schedule.Status, tt.occurrences, tt.nextRunAt, tt.status)
+		}
+	}
+}
+
+func TestScheduleAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)
+	schedule := ScheduledTransfer{Frequency: ScheduleFrequencyMonthly, StartAt: start, Status: ScheduleStatusActive}
+
+	schedule.Advance(start)
+	if schedule.Occurrences != 1 || !schedule.NextRunAt.Equal(time.Date(2026, 2, 28, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Advance() = %d occurrences, next run %s; want 1, 2026-02-28", schedule.Occurrences, schedule.NextRunAt)
+	}
+	schedule.Advance(time.Date(2026, 2, 28, 8, 0, 0, 0, time.UTC))
+	if schedule.Occurrences != 2 || !schedule.NextRunAt.Equal(time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Advance() = %d occurrences, next run %s; want 2, 2026-03-31", schedule.Occurrences, schedule.NextRunAt)
+	}
+	if schedule.Status != ScheduleStatusActive {
+		t.Errorf("Status = %s, want active", schedule.Status)
+	}
+}
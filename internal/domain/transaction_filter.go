@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TransactionFilter narrows a transaction history. Zero fields match everything.
+type TransactionFilter struct {
+	Currency           string               // Only wallets of this currency
+	From               *time.Time           // Created at or after
+	To                 *time.Time           // Created before
+	Direction          TransactionDirection // Relative to the wallets the history is read for
+	Status             TransactionStatus
+	Type               TransactionType
+	CounterpartyUserID int64  // Other side of the transaction
+	Reference          string // Client reference the sender attached
+	MinAmount          *Money // Inclusive, compared with the amount sent
+	MaxAmount          *Money // Inclusive, compared with the amount sent
+}
+
+// TransactionStream hands transactions to fn one at a time and stops at the first error fn
+// returns, so a long history never has to fit in memory
+type TransactionStream func(fn func(Transaction) error) error
+
+// Validate rejects unknown enum values and empty ranges
+func (f *TransactionFilter) Validate() error {
+	if f.Direction != "" && !f.Direction.Valid() {
+		return fmt.Errorf("%w: unknown direction %q", ErrInvalidFilter, f.Direction)
+	}
+	if f.Status != "" && !f.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, f.Status)
+	}
+	if f.Type != "" && !f.Type.Valid() {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidFilter, f.Type)
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidFilter)
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && f.MaxAmount.LessThan(*f.MinAmount) {
+		return fmt.Errorf("%w: min_amount must not exceed max_amount", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// Valid reports whether d is a known direction
+func (d TransactionDirection) Valid() bool {
+	return d == TransactionDirectionIn || d == TransactionDirectionOut
+}
+
+// Valid reports whether s is a known transaction status
+func (s TransactionStatus) Valid() bool {
+	switch s {
+	case TransactionStatusPending, TransactionStatusSuccess, TransactionStatusFailed:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether t is a known transaction type
+func (t TransactionType) Valid() bool {
+	switch t {
+	case TransactionTypeTopUp, TransactionTypeTransfer, TransactionTypeWithdrawal, TransactionTypeFee,
+		TransactionTypeAdjustment, TransactionTypeRefund, TransactionTypeCapture, TransactionTypePocket, TransactionTypeExchange:
+		return true
+	}
+	return false
+}
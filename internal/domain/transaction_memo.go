@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxDescriptionLength     = 255
+	MaxClientReferenceLength = 64
+	MaxMetadataSize          = 4096 // Bytes of compact JSON
+)
+
+// TransferMemo is what a sender attaches to a transfer: a description shown to both sides, a
+// reference of the sender's own system and free-form metadata
+type TransferMemo struct {
+	Description     string
+	ClientReference string // Unique per sending user
+	Metadata        Metadata
+}
+
+// Normalize trims the memo, compacts the metadata and checks their limits
+func (m *TransferMemo) Normalize() error {
+	m.Description = strings.TrimSpace(m.Description)
+	if utf8.RuneCountInString(m.Description) > MaxDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", ErrInvalidMemo, MaxDescriptionLength)
+	}
+	m.ClientReference = strings.TrimSpace(m.ClientReference)
+	if len(m.ClientReference) > MaxClientReferenceLength {
+		return fmt.Errorf("%w: client_reference must be at most %d characters", ErrInvalidMemo, MaxClientReferenceLength)
+	}
+	for i := 0; i < len(m.ClientReference); i++ {
+		if m.ClientReference[i] < 0x21 || m.ClientReference[i] > 0x7E {
+			return fmt.Errorf("%w: client_reference must be printable ASCII without spaces", ErrInvalidMemo)
+		}
+	}
+
+	if len(m.Metadata) == 0 || bytes.Equal(m.Metadata, []byte("null")) {
+		m.Metadata = nil
+		return nil
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, m.Metadata); err != nil || compact.Bytes()[0] != '{' {
+		return fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidMemo)
+	}
+	if compact.Len() > MaxMetadataSize {
+		return fmt.Errorf("%w: metadata must be at most %d bytes", ErrInvalidMemo, MaxMetadataSize)
+	}
+	m.Metadata = compact.Bytes()
+	return nil
+}
+
+// ApplyTo copies the memo onto a transaction about to be recorded
+func (m *TransferMemo) ApplyTo(t *Transaction) {
+	if m.Description != "" {
+		description := m.Description
+		t.Description = &description
+	}
+	if m.ClientReference != "" {
+		reference := m.ClientReference
+		t.ClientReference = &reference
+	}
+	t.Metadata = m.Metadata
+}
+
+// Metadata is a JSON object stored as is in a JSON column
+type Metadata []byte
+
+// MarshalJSON writes the stored object, or null when there is none
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	if len(m) == 0 {
+		return []byte("null"), nil
+	}
+	return m, nil
+}
+
+// UnmarshalJSON keeps a copy of the raw value; TransferMemo.Normalize checks it
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	*m = append((*m)[:0], data...)
+	return nil
+}
+
+// Value implements driver.Valuer, writing NULL when there is no metadata
+func (m Metadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return string(m), nil
+}
+
+// Scan implements sql.Scanner for JSON columns
+func (m *Metadata) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+	case []byte:
+		*m = append(Metadata(nil), v...) // The driver reuses its buffer
+	case string:
+		*m = Metadata(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Metadata", src)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+// CanDebit returns why money cannot leave the wallet, nil when it can
+func (w *Wallet) CanDebit() error {
+	if w.ArchivedAt != nil {
+		return ErrWalletArchived
+	}
+	switch w.Status {
+	case WalletStatusDebitFrozen, WalletStatusFrozen:
+		return ErrWalletFrozen
+	case WalletStatusClosed:
+		return ErrWalletClosed
+	}
+	return nil
+}
+
+// CanCredit returns why money cannot enter the wallet, nil when it can
+func (w *Wallet) CanCredit() error {
+	if w.ArchivedAt != nil {
+		return ErrWalletArchived
+	}
+	switch w.Status {
+	case WalletStatusFrozen:
+		return ErrWalletFrozen
+	case WalletStatusClosed:
+		return ErrWalletClosed
+	}
+	return nil
+}
+
+// Valid reports whether s is a known wallet status
+func (s WalletStatus) Valid() bool {
+	switch s {
+	case WalletStatusActive, WalletStatusDebitFrozen, WalletStatusFrozen, WalletStatusClosed:
+		return true
+	}
+	return false
+}
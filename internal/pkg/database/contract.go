@@ -36,6 +36,15 @@ type Meta struct {
 	PageNext uint64 `json:"page_next"`
 }
 
+// NewMeta describes page of a list of total items. PageNext is 0 on the last page.
+func NewMeta(total, page, limit uint64) *Meta {
+	meta := &Meta{Total: total, Page: page, Limit: limit}
+	if page*limit < total {
+		meta.PageNext = page + 1
+	}
+	return meta
+}
+
 type Adapter interface {
 	Builder() *goqu.Database
 	Meta(ctx context.Context, builder *goqu.SelectDataset, limit, page uint64) *Meta
@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/doug-martin/goqu/v9"
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -23,6 +24,10 @@ func InitMySQL() *sql.DB {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		dbUser, dbPass, dbHost, dbPort, dbName)
 
+	// goqu writes times in UTC by default; write them in the same zone the driver reads them
+	// back in (loc=Local) so day and month windows do not shift by the UTC offset
+	goqu.SetTimeLocation(time.Local)
+
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		log.Fatalf("Failed to open database connection: %v", err)
@@ -0,0 +1,84 @@
+// Package payout contains payout provider implementations
+package payout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+)
+
+// FailingAccountPrefix makes the log provider reject payouts, so the failure path can be exercised locally
+const FailingAccountPrefix = "999"
+
+// LogProvider is a stand-in payout provider for local use. It settles every payout
+// immediately and appends it to a JSON lines file, or to the info log when no file is set.
+type LogProvider struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLogProvider creates a log based payout provider. An empty path logs to stdout.
+func NewLogProvider(path string) domain.PayoutProvider {
+	return &LogProvider{path: path}
+}
+
+type payoutLine struct {
+	TransactionID int64     `json:"transaction_id"`
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	BankCode      string    `json:"bank_code"`
+	AccountNumber string    `json:"account_number"`
+	AccountName   string    `json:"account_name"`
+	Status        string    `json:"status"`
+	Reference     string    `json:"reference"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (p *LogProvider) Payout(ctx context.Context, req domain.PayoutRequest) (*domain.PayoutResult, error) {
+	result := &domain.PayoutResult{
+		Status:    domain.TransactionStatusSuccess,
+		Reference: fmt.Sprintf("LOG-%d-%d", req.TransactionID, time.Now().UnixNano()),
+	}
+	if strings.HasPrefix(req.Account.AccountNumber, FailingAccountPrefix) {
+		result.Status = domain.TransactionStatusFailed
+		result.FailureReason = "account rejected by bank"
+	}
+
+	line, err := json.Marshal(payoutLine{
+		TransactionID: req.TransactionID,
+		Amount:        req.Amount.String(),
+		Currency:      req.Amount.Currency,
+		BankCode:      req.Account.BankCode,
+		AccountNumber: req.Account.AccountNumber,
+		AccountName:   req.Account.AccountName,
+		Status:        string(result.Status),
+		Reference:     result.Reference,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.path == "" {
+		utils.LogInfof("[PAYOUT] %s", line)
+		return result, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open payout log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write payout log: %w", err)
+	}
+	return result, nil
+}
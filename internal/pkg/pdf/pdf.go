@@ -0,0 +1,151 @@
+// Package pdf writes plain text documents as PDF. Pages are A4 and set in Courier, so columns
+// line up by padding text with spaces; there are no images, tables or embedded fonts.
+package pdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4 in points
+	pageHeight   = 842
+	margin       = 40
+	fontSize     = 8
+	leading      = 11
+	linesPerPage = (pageHeight - 2*margin - 2*leading) / leading // Leaves room for the footer
+
+	// LineWidth is how many characters fit on a line; longer lines run off the page
+	LineWidth = (pageWidth - 2*margin) * 10 / (fontSize * 6) // Courier glyphs are 0.6 em wide
+)
+
+type line struct {
+	text string
+	bold bool
+}
+
+// Document collects lines and breaks them into pages
+type Document struct {
+	title string
+	pages [][]line
+}
+
+// New starts an empty document. The title goes into the document properties and the footer.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Line adds a line of text, starting a new page when the current one is full
+func (d *Document) Line(text string) {
+	d.add(line{text: text})
+}
+
+// Bold adds a line of text in bold
+func (d *Document) Bold(text string) {
+	d.add(line{text: text, bold: true})
+}
+
+// PageBreak makes the next line start on a new page
+func (d *Document) PageBreak() {
+	d.pages = append(d.pages, nil)
+}
+
+func (d *Document) add(l line) {
+	if len(d.pages) == 0 || len(d.pages[len(d.pages)-1]) == linesPerPage {
+		d.pages = append(d.pages, nil)
+	}
+	d.pages[len(d.pages)-1] = append(d.pages[len(d.pages)-1], l)
+}
+
+// WriteTo encodes the document. Object 1 is the catalog, 2 the page tree, 3 and 4 the fonts,
+// 5 the document information and every page then takes two objects: itself and its content.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = [][]line{nil}
+	}
+
+	out := &counter{w: bufio.NewWriter(w)}
+	var offsets []int64
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	fmt.Fprint(out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (wallet-api) >>", escape(d.title)))
+
+	for i, lines := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT\n%d TL\n%d %d Td\n", leading, margin, pageHeight-margin-fontSize)
+		for _, l := range lines {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "/%s %d Tf (%s) Tj T*\n", font, fontSize, escape(l.text))
+		}
+		fmt.Fprintf(&content, "ET\nBT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", fontSize, margin, margin,
+			escape(fmt.Sprintf("%s - page %d of %d", d.title, i+1, len(pages))))
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if out.err != nil {
+		return out.n, out.err
+	}
+	return out.n, out.w.Flush()
+}
+
+// escape makes text safe inside a PDF string. Characters outside printable ASCII become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7E:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// counter tracks the byte offset of each object for the cross-reference table
+type counter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
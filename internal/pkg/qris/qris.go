@@ -0,0 +1,274 @@
+// Package qris encodes and decodes merchant-presented QR payloads in the EMVCo format used by
+// QRIS. A payload is a list of ID-length-value fields ending with a CRC16 checksum.
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field IDs of the EMVCo merchant-presented mode that this package reads or writes
+const (
+	idPayloadFormat      = "00"
+	idInitiationMethod   = "01"
+	idMerchantCategory   = "52"
+	idCurrency           = "53"
+	idAmount             = "54"
+	idCountryCode        = "58"
+	idMerchantName       = "59"
+	idMerchantCity       = "60"
+	idPostalCode         = "61"
+	idAdditionalData     = "62"
+	idCRC                = "63"
+	idAccountGUI         = "00" // Inside a merchant account template
+	idAccountMerchantID  = "01" // Inside a merchant account template
+	idAdditionalBill     = "01" // Inside the additional data template
+	idAdditionalRefLabel = "05" // Inside the additional data template
+
+	firstAccountID = 26
+	lastAccountID  = 51
+
+	payloadFormatVersion = "01"
+	initiationStatic     = "11"
+	initiationDynamic    = "12"
+	crcFieldHeader       = idCRC + "04"
+)
+
+var (
+	ErrMalformed = errors.New("malformed QR payload")
+	ErrChecksum  = errors.New("QR payload checksum mismatch")
+)
+
+// numericCurrencies maps ISO-4217 alphabetic codes to the numeric codes EMVCo uses
+var numericCurrencies = map[string]string{
+	"IDR": "360",
+	"USD": "840",
+	"SGD": "702",
+	"MYR": "458",
+	"THB": "764",
+	"EUR": "978",
+	"JPY": "392",
+}
+
+// MerchantAccount is a merchant account information template (IDs 26 to 51)
+type MerchantAccount struct {
+	ID         string // Template ID, e.g. "26"
+	GUI        string // Globally unique identifier of the network, e.g. "ID.CO.QRIS.WWW"
+	MerchantID string
+}
+
+// Payload is a decoded merchant-presented QR code
+type Payload struct {
+	Dynamic              bool // Dynamic codes carry an amount and are meant for one payment
+	MerchantAccounts     []MerchantAccount
+	MerchantCategoryCode string // ISO 18245, e.g. "5499"
+	Currency             string // ISO-4217 alphabetic, e.g. "IDR"
+	Amount               string // Decimal, empty when the payer enters it
+	CountryCode          string // ISO 3166-1 alpha-2, e.g. "ID"
+	MerchantName         string
+	MerchantCity         string
+	PostalCode           string
+	BillNumber           string
+	ReferenceLabel       string
+}
+
+// Account returns the merchant account template of a network
+func (p *Payload) Account(gui string) (MerchantAccount, bool) {
+	for _, account := range p.MerchantAccounts {
+		if account.GUI == gui {
+			return account, true
+		}
+	}
+	return MerchantAccount{}, false
+}
+
+// Encode builds the payload string, checksum included
+func Encode(p Payload) (string, error) {
+	currency, ok := numericCurrencies[p.Currency]
+	if !ok {
+		return "", fmt.Errorf("qris: unsupported currency %q", p.Currency)
+	}
+	if len(p.MerchantAccounts) == 0 {
+		return "", errors.New("qris: a merchant account is required")
+	}
+	if p.Dynamic && p.Amount == "" {
+		return "", errors.New("qris: dynamic codes need an amount")
+	}
+
+	var b builder
+	b.add(idPayloadFormat, payloadFormatVersion)
+	if p.Dynamic {
+		b.add(idInitiationMethod, initiationDynamic)
+	} else {
+		b.add(idInitiationMethod, initiationStatic)
+	}
+	for _, account := range p.MerchantAccounts {
+		var template builder
+		template.add(idAccountGUI, account.GUI)
+		template.add(idAccountMerchantID, account.MerchantID)
+		b.add(account.ID, template.String())
+	}
+	b.add(idMerchantCategory, p.MerchantCategoryCode)
+	b.add(idCurrency, currency)
+	b.add(idAmount, p.Amount)
+	b.add(idCountryCode, p.CountryCode)
+	b.add(idMerchantName, p.MerchantName)
+	b.add(idMerchantCity, p.MerchantCity)
+	b.add(idPostalCode, p.PostalCode)
+	if p.BillNumber != "" || p.ReferenceLabel != "" {
+		var additional builder
+		additional.add(idAdditionalBill, p.BillNumber)
+		additional.add(idAdditionalRefLabel, p.ReferenceLabel)
+		b.add(idAdditionalData, additional.String())
+	}
+	if b.err != nil {
+		return "", b.err
+	}
+
+	body := b.String() + crcFieldHeader
+	return body + fmt.Sprintf("%04X", CRC16(body)), nil
+}
+
+// Decode parses a payload string and checks its CRC
+func Decode(payload string) (*Payload, error) {
+	payload = strings.TrimSpace(payload)
+	if len(payload) < len(crcFieldHeader)+4 {
+		return nil, ErrMalformed
+	}
+	body, checksum := payload[:len(payload)-4], payload[len(payload)-4:]
+	if !strings.HasSuffix(body, crcFieldHeader) {
+		return nil, fmt.Errorf("%w: the checksum must be the last field", ErrMalformed)
+	}
+	want, err := strconv.ParseUint(checksum, 16, 16)
+	if err != nil || uint16(want) != CRC16(body) {
+		return nil, ErrChecksum
+	}
+
+	fields, err := parse(body[:len(body)-len(crcFieldHeader)])
+	if err != nil {
+		return nil, err
+	}
+	if fields[idPayloadFormat] != payloadFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported payload format %q", ErrMalformed, fields[idPayloadFormat])
+	}
+
+	p := &Payload{
+		MerchantCategoryCode: fields[idMerchantCategory],
+		Amount:               fields[idAmount],
+		CountryCode:          fields[idCountryCode],
+		MerchantName:         fields[idMerchantName],
+		MerchantCity:         fields[idMerchantCity],
+		PostalCode:           fields[idPostalCode],
+	}
+	switch fields[idInitiationMethod] {
+	case initiationStatic, "":
+	case initiationDynamic:
+		p.Dynamic = true
+	default:
+		return nil, fmt.Errorf("%w: unknown point of initiation %q", ErrMalformed, fields[idInitiationMethod])
+	}
+	for alpha, numeric := range numericCurrencies {
+		if numeric == fields[idCurrency] {
+			p.Currency = alpha
+		}
+	}
+	if p.Currency == "" {
+		return nil, fmt.Errorf("%w: unsupported currency %q", ErrMalformed, fields[idCurrency])
+	}
+	if p.MerchantName == "" || p.MerchantCity == "" || p.CountryCode == "" {
+		return nil, fmt.Errorf("%w: merchant name, city and country are required", ErrMalformed)
+	}
+
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if n, _ := strconv.Atoi(id); n < firstAccountID || n > lastAccountID {
+			continue
+		}
+		template, err := parse(fields[id])
+		if err != nil {
+			return nil, err
+		}
+		p.MerchantAccounts = append(p.MerchantAccounts, MerchantAccount{
+			ID:         id,
+			GUI:        template[idAccountGUI],
+			MerchantID: template[idAccountMerchantID],
+		})
+	}
+	if additional, ok := fields[idAdditionalData]; ok {
+		template, err := parse(additional)
+		if err != nil {
+			return nil, err
+		}
+		p.BillNumber = template[idAdditionalBill]
+		p.ReferenceLabel = template[idAdditionalRefLabel]
+	}
+	return p, nil
+}
+
+// CRC16 is the CRC-16/CCITT-FALSE checksum (polynomial 0x1021, initial value 0xFFFF) EMVCo
+// computes over the payload up to and including the ID and length of the CRC field
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// parse splits a run of ID-length-value fields
+func parse(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, ErrMalformed
+		}
+		id := data[:2]
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || length < 1 || len(data) < 4+length {
+			return nil, fmt.Errorf("%w: bad length in field %s", ErrMalformed, id)
+		}
+		if _, seen := fields[id]; seen {
+			return nil, fmt.Errorf("%w: field %s appears twice", ErrMalformed, id)
+		}
+		fields[id] = data[4 : 4+length]
+		data = data[4+length:]
+	}
+	return fields, nil
+}
+
+// builder writes ID-length-value fields, skipping empty values
+type builder struct {
+	strings.Builder
+	err error
+}
+
+func (b *builder) add(id, value string) {
+	if value == "" || b.err != nil {
+		return
+	}
+	if len(value) > 99 {
+		b.err = fmt.Errorf("qris: field %s is longer than 99 characters", id)
+		return
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7E {
+			b.err = fmt.Errorf("qris: field %s must be printable ASCII", id)
+			return
+		}
+	}
+	fmt.Fprintf(b, "%s%02d%s", id, len(value), value)
+}
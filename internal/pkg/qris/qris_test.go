@@ -0,0 +1,137 @@
+package qris
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	// Check value of CRC-16/CCITT-FALSE
+	if got := CRC16("123456789"); got != 0x29B1 {
+		t.Errorf("CRC16(123456789) = %04X, want 29B1", got)
+	}
+	if got := CRC16(""); got != 0xFFFF {
+		t.Errorf("CRC16() = %04X, want FFFF", got)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload Payload
+	}{
+		{
+			name: "static",
+			payload: Payload{
+				MerchantAccounts:     []MerchantAccount{{ID: "26", GUI: "ID.TLAB.WALLET", MerchantID: "a1b2c3d4e5f6g7h8"}},
+				MerchantCategoryCode: "0000",
+				Currency:             "IDR",
+				CountryCode:          "ID",
+				MerchantName:         "Toko Kopi",
+				MerchantCity:         "Jakarta",
+			},
+		},
+		{
+			name: "dynamic with bill number",
+			payload: Payload{
+				Dynamic: true,
+				MerchantAccounts: []MerchantAccount{
+					{ID: "26", GUI: "ID.TLAB.WALLET", MerchantID: "a1b2c3d4e5f6g7h8"},
+					{ID: "51", GUI: "ID.CO.QRIS.WWW", MerchantID: "ID1020021181745"},
+				},
+				MerchantCategoryCode: "5499",
+				Currency:             "USD",
+				Amount:               "12.50",
+				CountryCode:          "ID",
+				MerchantName:         "Toko Kopi",
+				MerchantCity:         "Bandung",
+				PostalCode:           "40111",
+				BillNumber:           "INV-001",
+				ReferenceLabel:       "REF42",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := Encode(tt.payload)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if !strings.HasPrefix(encoded, "000201") {
+				t.Errorf("Encode() = %q, want the payload format field first", encoded)
+			}
+			decoded, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode(%q) error = %v", encoded, err)
+			}
+			if !reflect.DeepEqual(*decoded, tt.payload) {
+				t.Errorf("Decode(Encode()) = %+v, want %+v", *decoded, tt.payload)
+			}
+		})
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	encoded, err := Encode(Payload{
+		MerchantAccounts:     []MerchantAccount{{ID: "26", GUI: "ID.TLAB.WALLET", MerchantID: "a1b2c3d4e5f6g7h8"}},
+		MerchantCategoryCode: "0000",
+		Currency:             "IDR",
+		CountryCode:          "ID",
+		MerchantName:         "Toko Kopi",
+		MerchantCity:         "Jakarta",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := strings.Replace(encoded, "Toko Kopi", "Toko Kopo", 1)
+	if _, err := Decode(tampered); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Decode(tampered body) error = %v, want ErrChecksum", err)
+	}
+	checksum := encoded[len(encoded)-4:]
+	wrong := "0000"
+	if checksum == wrong {
+		wrong = "FFFF"
+	}
+	if _, err := Decode(encoded[:len(encoded)-4] + wrong); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Decode(wrong checksum) error = %v, want ErrChecksum", err)
+	}
+	if _, err := Decode("000201"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decode(short) error = %v, want ErrMalformed", err)
+	}
+
+	// A checksum that is not the last field
+	body := "000201" + crcFieldHeader
+	withTrailer := body + "0000" + "5802ID"
+	if _, err := Decode(withTrailer); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decode(trailing field) error = %v, want ErrMalformed", err)
+	}
+}
+
+func TestEncodeRejects(t *testing.T) {
+	base := Payload{
+		MerchantAccounts: []MerchantAccount{{ID: "26", GUI: "ID.TLAB.WALLET", MerchantID: "token"}},
+		Currency:         "IDR",
+		CountryCode:      "ID",
+		MerchantName:     "Toko Kopi",
+		MerchantCity:     "Jakarta",
+	}
+
+	unsupported := base
+	unsupported.Currency = "XYZ"
+	if _, err := Encode(unsupported); err == nil {
+		t.Error("Encode() with an unsupported currency returned no error")
+	}
+	noAccount := base
+	noAccount.MerchantAccounts = nil
+	if _, err := Encode(noAccount); err == nil {
+		t.Error("Encode() without a merchant account returned no error")
+	}
+	noAmount := base
+	noAmount.Dynamic = true
+	if _, err := Encode(noAmount); err == nil {
+		t.Error("Encode() of a dynamic code without an amount returned no error")
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// CSVText makes free text safe to put in a CSV cell. Spreadsheets run a cell that starts with
+// =, +, -, @, a tab or a carriage return as a formula, so such text gets a leading quote.
+func CSVText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
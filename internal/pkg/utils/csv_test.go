@@ -0,0 +1,25 @@
+package utils
+
+import "testing"
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"Lunch", "Lunch"},
+		{"Rent: =half", "Rent: =half"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+	}
+	for _, tt := range tests {
+		if got := CSVText(tt.text); got != tt.want {
+			t.Errorf("CSVText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,30 @@
 package utils
 
 import (
+	"wallet-api/internal/pkg/database"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // ApiResponse defines the standard response format for the API
 type ApiResponse struct {
-	Success bool        `json:"success"`
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
+	Success bool           `json:"success"`
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    interface{}    `json:"data,omitempty"`
+	Meta    *database.Meta `json:"meta,omitempty"` // Set on paged lists
+	Error   interface{}    `json:"error,omitempty"`
 }
 
 // Success sends a successful JSON response with a standard format.
 // The default status code is 200 (OK), but can be customized.
 func Success(c *fiber.Ctx, code int, message string, data interface{}) error {
+	return SuccessWithMeta(c, code, message, data, nil)
+}
+
+// SuccessWithMeta sends a successful JSON response for a page of a list, with the paging
+// metadata in meta
+func SuccessWithMeta(c *fiber.Ctx, code int, message string, data interface{}, meta *database.Meta) error {
 	if code == 0 {
 		code = fiber.StatusOK
 	}
@@ -27,6 +36,7 @@ func Success(c *fiber.Ctx, code int, message string, data interface{}) error {
 		Code:    code,
 		Message: message,
 		Data:    data,
+		Meta:    meta,
 	})
 }
 
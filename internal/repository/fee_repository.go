@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlFeeRuleRepo handles fee rules
+type MysqlFeeRuleRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlFeeRuleRepository creates a new fee rule repository
+func NewMysqlFeeRuleRepository(db *sql.DB) domain.FeeRuleRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlFeeRuleRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlFeeRuleRepo) List(ctx context.Context) ([]domain.FeeRule, error) {
+	var rules []domain.FeeRule
+	err := r.db.From("fee_rules").
+		Order(goqu.C("operation").Asc(), goqu.C("currency").Asc(), goqu.C("min_amount").Asc()).
+		ScanStructsContext(ctx, &rules)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		rules[i].ApplyCurrency()
+	}
+	return rules, nil
+}
+
+// Match returns the tier with the highest lower bound not above amount
+func (r *MysqlFeeRuleRepo) Match(ctx context.Context, operation domain.FeeOperation, amount domain.Money) (*domain.FeeRule, error) {
+	var rule domain.FeeRule
+	found, err := r.db.From("fee_rules").
+		Where(
+			goqu.C("operation").Eq(operation),
+			goqu.C("currency").Eq(amount.Currency),
+			goqu.C("min_amount").Lte(amount),
+		).
+		Order(goqu.C("min_amount").Desc()).
+		Limit(1).
+		ScanStructContext(ctx, &rule)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	rule.ApplyCurrency()
+	return &rule, nil
+}
+
+// Upsert replaces the rule of a tier, creating it if needed
+func (r *MysqlFeeRuleRepo) Upsert(ctx context.Context, rule *domain.FeeRule) error {
+	record := goqu.Record{
+		"flat_fee":    rule.FlatFee,
+		"percent_bps": rule.PercentBps,
+		"min_fee":     rule.MinFee,
+		"max_fee":     rule.MaxFee,
+		"updated_at":  time.Now(),
+	}
+
+	var existingID int64
+	found, err := r.db.From("fee_rules").
+		Select("id").
+		Where(
+			goqu.C("operation").Eq(rule.Operation),
+			goqu.C("currency").Eq(rule.Currency),
+			goqu.C("min_amount").Eq(rule.MinAmount),
+		).
+		ScanValContext(ctx, &existingID)
+	if err != nil {
+		return err
+	}
+	if found {
+		rule.ID = existingID
+		_, err = r.db.Update("fee_rules").
+			Set(record).
+			Where(goqu.C("id").Eq(existingID)).
+			Executor().ExecContext(ctx)
+		return err
+	}
+
+	record["operation"] = rule.Operation
+	record["currency"] = rule.Currency
+	record["min_amount"] = rule.MinAmount
+	result, err := r.db.Insert("fee_rules").Rows(record).Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rule.ID = id
+	return nil
+}
+
+func (r *MysqlFeeRuleRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Delete("fee_rules").
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlFXRepo handles exchange rates and quotes
+type MysqlFXRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlFXRepository creates a new FX repository
+func NewMysqlFXRepository(db *sql.DB) domain.FXRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlFXRepo{db: dialect.DB(db)}
+}
+
+// UpsertRate inserts a rate or replaces the existing rate of the pair
+func (r *MysqlFXRepo) UpsertRate(ctx context.Context, rate *domain.FXRate) error {
+	existing, err := r.GetRate(ctx, rate.BaseCurrency, rate.QuoteCurrency)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		_, err = r.db.Update("fx_rates").
+			Set(goqu.Record{
+				"rate":       rate.Rate,
+				"spread_bps": rate.SpreadBps,
+				"updated_at": goqu.L("NOW()"),
+			}).
+			Where(goqu.C("id").Eq(existing.ID)).
+			Executor().ExecContext(ctx)
+		rate.ID = existing.ID
+		return err
+	}
+
+	result, err := r.db.Insert("fx_rates").
+		Rows(goqu.Record{
+			"base_currency":  rate.BaseCurrency,
+			"quote_currency": rate.QuoteCurrency,
+			"rate":           rate.Rate,
+			"spread_bps":     rate.SpreadBps,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err == nil {
+		rate.ID = id
+	}
+	return err
+}
+
+func (r *MysqlFXRepo) GetRate(ctx context.Context, baseCurrency, quoteCurrency string) (*domain.FXRate, error) {
+	var rate domain.FXRate
+	found, err := r.db.From("fx_rates").
+		Where(
+			goqu.C("base_currency").Eq(baseCurrency),
+			goqu.C("quote_currency").Eq(quoteCurrency),
+		).
+		ScanStructContext(ctx, &rate)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &rate, nil
+}
+
+func (r *MysqlFXRepo) ListRates(ctx context.Context) ([]domain.FXRate, error) {
+	var rates []domain.FXRate
+	err := r.db.From("fx_rates").
+		Order(goqu.C("base_currency").Asc(), goqu.C("quote_currency").Asc()).
+		ScanStructsContext(ctx, &rates)
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *MysqlFXRepo) CreateQuote(ctx context.Context, quote *domain.FXQuote) error {
+	_, err := r.db.Insert("fx_quotes").
+		Rows(goqu.Record{
+			"id":              quote.ID,
+			"user_id":         quote.UserID,
+			"source_currency": quote.SourceCurrency,
+			"target_currency": quote.TargetCurrency,
+			"source_amount":   quote.SourceAmount,
+			"target_amount":   quote.TargetAmount,
+			"mid_rate":        quote.MidRate,
+			"rate":            quote.Rate,
+			"spread_bps":      quote.SpreadBps,
+			"spread":          quote.Spread,
+			"expires_at":      quote.ExpiresAt,
+			"created_at":      quote.CreatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// GetQuoteForUpdate locks a quote so it can be used at most once
+func (r *MysqlFXRepo) GetQuoteForUpdate(ctx context.Context, tx interface{}, id string) (*domain.FXQuote, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+
+	var quote domain.FXQuote
+	found, err := txDb.From("fx_quotes").
+		Where(goqu.C("id").Eq(id)).
+		ForUpdate(goqu.Wait).
+		ScanStructContext(ctx, &quote)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	quote.ApplyCurrency()
+	return &quote, nil
+}
+
+func (r *MysqlFXRepo) MarkQuoteUsedWithTx(ctx context.Context, tx interface{}, id string, transactionID int64, usedAt time.Time) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+
+	_, err := txDb.Update("fx_quotes").
+		Set(goqu.Record{
+			"used_at":        usedAt,
+			"transaction_id": transactionID,
+		}).
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
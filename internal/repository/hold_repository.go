@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlHoldRepo handles authorization holds
+type MysqlHoldRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlHoldRepository creates a new hold repository
+func NewMysqlHoldRepository(db *sql.DB) domain.HoldRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlHoldRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlHoldRepo) CreateWithTx(ctx context.Context, tx interface{}, hold *domain.Hold) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+
+	hold.Currency = hold.Amount.Currency
+	result, err := txDb.Insert("holds").
+		Rows(goqu.Record{
+			"wallet_id":        hold.WalletID,
+			"merchant_user_id": hold.MerchantUserID,
+			"amount":           hold.Amount,
+			"currency":         hold.Currency,
+			"status":           hold.Status,
+			"expires_at":       hold.ExpiresAt,
+			"created_at":       hold.CreatedAt,
+			"updated_at":       hold.UpdatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	hold.ID = id
+	return nil
+}
+
+// GetByIDForUpdate fetches a hold and locks its row until txDb ends
+func (r *MysqlHoldRepo) GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*domain.Hold, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+
+	var hold domain.Hold
+	found, err := txDb.From("holds").
+		Where(goqu.C("id").Eq(id)).
+		ForUpdate(goqu.Wait).
+		ScanStructContext(ctx, &hold)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	hold.ApplyCurrency()
+	return &hold, nil
+}
+
+func (r *MysqlHoldRepo) UpdateWithTx(ctx context.Context, tx interface{}, hold *domain.Hold) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+
+	_, err := txDb.Update("holds").
+		Set(goqu.Record{
+			"status":          hold.Status,
+			"captured_amount": hold.CapturedAmount,
+			"transaction_id":  hold.TransactionID,
+			"updated_at":      hold.UpdatedAt,
+		}).
+		Where(goqu.C("id").Eq(hold.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// ListExpired returns active holds whose expiry has passed, oldest first
+func (r *MysqlHoldRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.Hold, error) {
+	var holds []domain.Hold
+	err := r.db.From("holds").
+		Where(
+			goqu.C("status").Eq(domain.HoldStatusActive),
+			goqu.C("expires_at").Lte(now),
+		).
+		Order(goqu.C("expires_at").Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &holds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holds {
+		holds[i].ApplyCurrency()
+	}
+	return holds, nil
+}
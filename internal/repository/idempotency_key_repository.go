@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlIdempotencyKeyRepo handles the idempotency keys of retried requests
+type MysqlIdempotencyKeyRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlIdempotencyKeyRepository creates a new idempotency key repository
+func NewMysqlIdempotencyKeyRepository(db *sql.DB) domain.IdempotencyKeyRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlIdempotencyKeyRepo{db: dialect.DB(db)}
+}
+
+// Create claims the key for the user. It is an INSERT IGNORE so two concurrent requests with the
+// same key cannot both get it: the unique key lets exactly one insert through.
+func (r *MysqlIdempotencyKeyRepo) Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error) {
+	result, err := r.db.Insert("idempotency_keys").
+		Rows(goqu.Record{
+			"user_id":         key.UserID,
+			"idempotency_key": key.Key,
+			"fingerprint":     key.Fingerprint,
+			"status":          key.Status,
+			"expires_at":      key.ExpiresAt,
+		}).
+		OnConflict(goqu.DoNothing()).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	key.ID = id
+	return true, nil
+}
+
+func (r *MysqlIdempotencyKeyRepo) GetByUserIDAndKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error) {
+	var record domain.IdempotencyKey
+	found, err := r.db.From("idempotency_keys").
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("idempotency_key").Eq(key),
+		).
+		ScanStructContext(ctx, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *MysqlIdempotencyKeyRepo) Complete(ctx context.Context, id int64, code int, body []byte) error {
+	_, err := r.db.Update("idempotency_keys").
+		Set(goqu.Record{
+			"status":        domain.IdempotencyStatusCompleted,
+			"response_code": code,
+			"response_body": body,
+		}).
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (r *MysqlIdempotencyKeyRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Delete("idempotency_keys").
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// DeleteExpired removes every key past its expiry and returns how many there were
+func (r *MysqlIdempotencyKeyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.Delete("idempotency_keys").
+		Where(goqu.C("expires_at").Lte(now)).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlLedgerRepo handles ledger postings
+type MysqlLedgerRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlLedgerRepository creates a new ledger repository
+func NewMysqlLedgerRepository(db *sql.DB) domain.LedgerRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlLedgerRepo{db: dialect.DB(db)}
+}
+
+// CreateWithTx writes all entries of a journal. Postings are only ever written
+// inside the database transaction that changes the balances they describe.
+func (r *MysqlLedgerRepo) CreateWithTx(ctx context.Context, tx interface{}, entries []domain.LedgerEntry) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, goqu.Record{
+			"journal_id":     e.JournalID,
+			"transaction_id": e.TransactionID,
+			"wallet_id":      e.WalletID,
+			"account":        e.Account,
+			"direction":      e.Direction,
+			"amount":         e.Amount,
+			"currency":       e.Amount.Currency,
+			"balance_after":  e.BalanceAfter,
+			"created_at":     e.CreatedAt,
+		})
+	}
+
+	_, err := txDb.Insert("ledger_entries").
+		Rows(rows...).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (r *MysqlLedgerRepo) GetByWalletID(ctx context.Context, walletID int64, limit, offset int) ([]domain.LedgerEntry, error) {
+	var entries []domain.LedgerEntry
+	err := r.db.From("ledger_entries").
+		Where(goqu.C("wallet_id").Eq(walletID)).
+		Order(goqu.C("id").Desc()).
+		Limit(uint(limit)).
+		Offset(uint(offset)).
+		ScanStructsContext(ctx, &entries)
+
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].ApplyCurrency()
+	}
+	return entries, nil
+}
+
+// GetWalletBalance recomputes a wallet balance from its postings, which proves the cached wallets.balance
+func (r *MysqlLedgerRepo) GetWalletBalance(ctx context.Context, walletID int64) (domain.Money, error) {
+	var row struct {
+		Currency string       `db:"currency"`
+		Balance  domain.Money `db:"balance"`
+	}
+	found, err := r.db.From("ledger_entries").
+		Select(
+			goqu.C("currency"),
+			goqu.L("SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END)").As("balance"),
+		).
+		Where(goqu.C("wallet_id").Eq(walletID)).
+		GroupBy(goqu.C("currency")).
+		ScanStructContext(ctx, &row)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if !found {
+		return domain.NewMoney(0, ""), nil
+	}
+	return domain.NewMoney(row.Balance.MinorUnits, row.Currency), nil
+}
+
+// GetWalletBalances recomputes the balance of every wallet that has postings
+func (r *MysqlLedgerRepo) GetWalletBalances(ctx context.Context) (map[int64]domain.Money, error) {
+	var rows []struct {
+		WalletID int64        `db:"wallet_id"`
+		Currency string       `db:"currency"`
+		Balance  domain.Money `db:"balance"`
+	}
+	err := r.db.From("ledger_entries").
+		Select(
+			goqu.C("wallet_id"),
+			goqu.C("currency"),
+			goqu.L("SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END)").As("balance"),
+		).
+		Where(goqu.C("wallet_id").IsNotNull()).
+		GroupBy(goqu.C("wallet_id"), goqu.C("currency")).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int64]domain.Money, len(rows))
+	for _, row := range rows {
+		balances[row.WalletID] = domain.NewMoney(row.Balance.MinorUnits, row.Currency)
+	}
+	return balances, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlLimitRepo handles transfer limits
+type MysqlLimitRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlLimitRepository creates a new limit repository
+func NewMysqlLimitRepository(db *sql.DB) domain.LimitRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlLimitRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlLimitRepo) Get(ctx context.Context, userID int64, currency string) (*domain.TransferLimit, error) {
+	var limit domain.TransferLimit
+	found, err := r.db.From("transfer_limits").
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("currency").Eq(currency),
+		).
+		ScanStructContext(ctx, &limit)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	limit.ApplyCurrency()
+	return &limit, nil
+}
+
+// List returns every configured limit, global rows first
+func (r *MysqlLimitRepo) List(ctx context.Context) ([]domain.TransferLimit, error) {
+	var limits []domain.TransferLimit
+	err := r.db.From("transfer_limits").
+		Order(goqu.C("user_id").Asc(), goqu.C("currency").Asc()).
+		ScanStructsContext(ctx, &limits)
+	if err != nil {
+		return nil, err
+	}
+	for i := range limits {
+		limits[i].ApplyCurrency()
+	}
+	return limits, nil
+}
+
+// Upsert replaces the limits of a user and currency, creating the row if needed
+func (r *MysqlLimitRepo) Upsert(ctx context.Context, limit *domain.TransferLimit) error {
+	record := goqu.Record{
+		"per_transaction_max": limit.PerTransactionMax,
+		"daily_amount_max":    limit.DailyAmountMax,
+		"daily_count_max":     limit.DailyCountMax,
+		"monthly_amount_max":  limit.MonthlyAmountMax,
+		"monthly_count_max":   limit.MonthlyCountMax,
+		"updated_at":          time.Now(),
+	}
+	existing, err := r.Get(ctx, limit.UserID, limit.Currency)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		limit.ID = existing.ID
+		_, err = r.db.Update("transfer_limits").
+			Set(record).
+			Where(goqu.C("id").Eq(existing.ID)).
+			Executor().ExecContext(ctx)
+		return err
+	}
+
+	record["user_id"] = limit.UserID
+	record["currency"] = limit.Currency
+	result, err := r.db.Insert("transfer_limits").Rows(record).Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	limit.ID = id
+	return nil
+}
+
+func (r *MysqlLimitRepo) Delete(ctx context.Context, userID int64, currency string) error {
+	_, err := r.db.Delete("transfer_limits").
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("currency").Eq(currency),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// MysqlPaymentRequestRepo handles payment requests between users
+type MysqlPaymentRequestRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlPaymentRequestRepository creates a new payment request repository
+func NewMysqlPaymentRequestRepository(db *sql.DB) domain.PaymentRequestRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlPaymentRequestRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlPaymentRequestRepo) Create(ctx context.Context, request *domain.PaymentRequest) error {
+	request.Currency = request.Amount.Currency
+	result, err := r.db.Insert("payment_requests").
+		Rows(goqu.Record{
+			"requester_user_id": request.RequesterUserID,
+			"payer_user_id":     request.PayerUserID,
+			"amount":            request.Amount,
+			"currency":          request.Currency,
+			"note":              request.Note,
+			"status":            request.Status,
+			"expires_at":        request.ExpiresAt,
+			"created_at":        request.CreatedAt,
+			"updated_at":        request.UpdatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	request.ID = id
+	return nil
+}
+
+func (r *MysqlPaymentRequestRepo) GetByID(ctx context.Context, id int64) (*domain.PaymentRequest, error) {
+	var request domain.PaymentRequest
+	found, err := r.db.From("payment_requests").
+		Where(goqu.C("id").Eq(id)).
+		ScanStructContext(ctx, &request)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	request.ApplyCurrency()
+	return &request, nil
+}
+
+func (r *MysqlPaymentRequestRepo) GetByPayerID(ctx context.Context, payerUserID int64, status domain.PaymentRequestStatus, now time.Time) ([]domain.PaymentRequest, error) {
+	return r.list(ctx, goqu.C("payer_user_id").Eq(payerUserID), status, now)
+}
+
+func (r *MysqlPaymentRequestRepo) GetByRequesterID(ctx context.Context, requesterUserID int64, status domain.PaymentRequestStatus, now time.Time) ([]domain.PaymentRequest, error) {
+	return r.list(ctx, goqu.C("requester_user_id").Eq(requesterUserID), status, now)
+}
+
+// list filters by status the way callers see it: a pending request past its expiry is expired
+func (r *MysqlPaymentRequestRepo) list(ctx context.Context, owner exp.Expression, status domain.PaymentRequestStatus, now time.Time) ([]domain.PaymentRequest, error) {
+	query := r.db.From("payment_requests").Where(owner)
+	switch status {
+	case "":
+	case domain.PaymentRequestStatusPending:
+		query = query.Where(
+			goqu.C("status").Eq(domain.PaymentRequestStatusPending),
+			goqu.C("expires_at").Gt(now),
+		)
+	case domain.PaymentRequestStatusExpired:
+		query = query.Where(goqu.Or(
+			goqu.C("status").Eq(domain.PaymentRequestStatusExpired),
+			goqu.And(
+				goqu.C("status").Eq(domain.PaymentRequestStatusPending),
+				goqu.C("expires_at").Lte(now),
+			),
+		))
+	default:
+		query = query.Where(goqu.C("status").Eq(status))
+	}
+
+	var requests []domain.PaymentRequest
+	err := query.
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
+		ScanStructsContext(ctx, &requests)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		requests[i].ApplyCurrency()
+		requests[i].ApplyExpiry(now)
+	}
+	return requests, nil
+}
+
+// TransitionWithTx moves a request from one status to another if nobody else did first. Inside
+// tx the request row stays locked until tx ends; a nil tx runs on its own.
+func (r *MysqlPaymentRequestRepo) TransitionWithTx(ctx context.Context, tx interface{}, id int64, from, to domain.PaymentRequestStatus, now time.Time) (bool, error) {
+	db, err := txExecutor(r.db, tx)
+	if err != nil {
+		return false, err
+	}
+	conditions := []exp.Expression{
+		goqu.C("id").Eq(id),
+		goqu.C("status").Eq(from),
+	}
+	if from == domain.PaymentRequestStatusPending {
+		conditions = append(conditions, goqu.C("expires_at").Gt(now))
+	}
+
+	record := goqu.Record{"status": to, "updated_at": now}
+	if to == domain.PaymentRequestStatusPending {
+		record["responded_at"] = nil
+	} else {
+		record["responded_at"] = now
+	}
+
+	result, err := db.Update("payment_requests").
+		Set(record).
+		Where(conditions...).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *MysqlPaymentRequestRepo) SetTransactionWithTx(ctx context.Context, tx interface{}, id int64, transactionID int64) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+	_, err := txDb.Update("payment_requests").
+		Set(goqu.Record{"transaction_id": transactionID}).
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// MysqlQRCodeRepo handles merchant-presented QR codes
+type MysqlQRCodeRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlQRCodeRepository creates a new QR code repository
+func NewMysqlQRCodeRepository(db *sql.DB) domain.QRCodeRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlQRCodeRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlQRCodeRepo) Create(ctx context.Context, code *domain.QRCode) error {
+	result, err := r.db.Insert("qr_codes").
+		Rows(goqu.Record{
+			"wallet_id":     code.WalletID,
+			"type":          code.Type,
+			"token":         code.Token,
+			"amount":        code.Amount,
+			"currency":      code.Currency,
+			"merchant_name": code.MerchantName,
+			"merchant_city": code.MerchantCity,
+			"reference":     code.Reference,
+			"payload":       code.Payload,
+			"status":        code.Status,
+			"expires_at":    code.ExpiresAt,
+			"created_at":    code.CreatedAt,
+			"updated_at":    code.UpdatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	code.ID = id
+	return nil
+}
+
+func (r *MysqlQRCodeRepo) GetByToken(ctx context.Context, token string) (*domain.QRCode, error) {
+	var code domain.QRCode
+	found, err := r.db.From("qr_codes").
+		Where(goqu.C("token").Eq(token)).
+		ScanStructContext(ctx, &code)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	code.ApplyCurrency()
+	return &code, nil
+}
+
+// GetByWalletIDs returns the codes of the given wallets, newest first
+func (r *MysqlQRCodeRepo) GetByWalletIDs(ctx context.Context, walletIDs []int64, now time.Time) ([]domain.QRCode, error) {
+	var codes []domain.QRCode
+	err := r.db.From("qr_codes").
+		Where(goqu.C("wallet_id").In(walletIDs)).
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
+		ScanStructsContext(ctx, &codes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range codes {
+		codes[i].ApplyCurrency()
+		codes[i].ApplyExpiry(now)
+	}
+	return codes, nil
+}
+
+// TransitionWithTx moves a code from one status to another if nobody else did first. The code
+// row stays locked until tx ends.
+func (r *MysqlQRCodeRepo) TransitionWithTx(ctx context.Context, tx interface{}, id int64, from, to domain.QRCodeStatus, now time.Time) (bool, error) {
+	db, err := txExecutor(r.db, tx)
+	if err != nil {
+		return false, err
+	}
+	conditions := []exp.Expression{
+		goqu.C("id").Eq(id),
+		goqu.C("status").Eq(from),
+	}
+	if from == domain.QRCodeStatusActive {
+		conditions = append(conditions, goqu.Or(
+			goqu.C("expires_at").IsNull(),
+			goqu.C("expires_at").Gt(now),
+		))
+	}
+
+	record := goqu.Record{"status": to, "updated_at": now}
+	if to == domain.QRCodeStatusPaid {
+		record["paid_at"] = now
+	} else {
+		record["paid_at"] = nil
+	}
+
+	result, err := db.Update("qr_codes").
+		Set(record).
+		Where(conditions...).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *MysqlQRCodeRepo) SetTransactionWithTx(ctx context.Context, tx interface{}, id int64, transactionID int64) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+	_, err := txDb.Update("qr_codes").
+		Set(goqu.Record{"transaction_id": transactionID}).
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlScheduleRepo handles scheduled transfers and their executions
+type MysqlScheduleRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlScheduleRepository creates a new scheduled transfer repository
+func NewMysqlScheduleRepository(db *sql.DB) domain.ScheduleRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlScheduleRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlScheduleRepo) Create(ctx context.Context, schedule *domain.ScheduledTransfer) error {
+	schedule.Currency = schedule.Amount.Currency
+	result, err := r.db.Insert("scheduled_transfers").
+		Rows(goqu.Record{
+			"user_id":          schedule.UserID,
+			"receiver_user_id": schedule.ReceiverUserID,
+			"amount":           schedule.Amount,
+			"currency":         schedule.Currency,
+			"frequency":        schedule.Frequency,
+			"start_at":         schedule.StartAt,
+			"end_at":           schedule.EndAt,
+			"next_run_at":      schedule.NextRunAt,
+			"status":           schedule.Status,
+			"created_at":       schedule.CreatedAt,
+			"updated_at":       schedule.UpdatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	schedule.ID = id
+	return nil
+}
+
+func (r *MysqlScheduleRepo) GetByID(ctx context.Context, id int64) (*domain.ScheduledTransfer, error) {
+	var schedule domain.ScheduledTransfer
+	found, err := r.db.From("scheduled_transfers").
+		Where(goqu.C("id").Eq(id)).
+		ScanStructContext(ctx, &schedule)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	schedule.ApplyCurrency()
+	return &schedule, nil
+}
+
+func (r *MysqlScheduleRepo) GetByUserID(ctx context.Context, userID int64) ([]domain.ScheduledTransfer, error) {
+	var schedules []domain.ScheduledTransfer
+	err := r.db.From("scheduled_transfers").
+		Where(goqu.C("user_id").Eq(userID)).
+		Order(goqu.C("id").Desc()).
+		ScanStructsContext(ctx, &schedules)
+	if err != nil {
+		return nil, err
+	}
+	for i := range schedules {
+		schedules[i].ApplyCurrency()
+	}
+	return schedules, nil
+}
+
+// Update persists every mutable field of a schedule
+func (r *MysqlScheduleRepo) Update(ctx context.Context, schedule *domain.ScheduledTransfer) error {
+	schedule.Currency = schedule.Amount.Currency
+	_, err := r.db.Update("scheduled_transfers").
+		Set(goqu.Record{
+			"amount":               schedule.Amount,
+			"currency":             schedule.Currency,
+			"frequency":            schedule.Frequency,
+			"start_at":             schedule.StartAt,
+			"end_at":               schedule.EndAt,
+			"next_run_at":          schedule.NextRunAt,
+			"occurrences":          schedule.Occurrences,
+			"attempts":             schedule.Attempts,
+			"consecutive_failures": schedule.ConsecutiveFailures,
+			"last_error":           schedule.LastError,
+			"status":               schedule.Status,
+			"updated_at":           schedule.UpdatedAt,
+		}).
+		Where(goqu.C("id").Eq(schedule.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// GetClaimedForUpdate locks a schedule that is still active and claimed until leaseUntil. It
+// returns nil when the schedule was cancelled, paused or retimed since it was claimed.
+func (r *MysqlScheduleRepo) GetClaimedForUpdate(ctx context.Context, tx interface{}, id int64, leaseUntil time.Time) (*domain.ScheduledTransfer, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+	var schedule domain.ScheduledTransfer
+	found, err := txDb.From("scheduled_transfers").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("status").Eq(domain.ScheduleStatusActive),
+			goqu.C("next_run_at").Eq(leaseUntil),
+		).
+		ForUpdate(goqu.Wait).
+		ScanStructContext(ctx, &schedule)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	schedule.ApplyCurrency()
+	return &schedule, nil
+}
+
+// RecordRunWithTx stores the outcome of a run: the columns the worker owns, and the status
+// when the run paused or completed the schedule. It only touches a schedule that is still
+// active and claimed until leaseUntil, so a change the user made meanwhile wins.
+func (r *MysqlScheduleRepo) RecordRunWithTx(ctx context.Context, tx interface{}, schedule *domain.ScheduledTransfer, leaseUntil time.Time) (bool, error) {
+	db, err := txExecutor(r.db, tx)
+	if err != nil {
+		return false, err
+	}
+	result, err := db.Update("scheduled_transfers").
+		Set(goqu.Record{
+			"next_run_at":          schedule.NextRunAt,
+			"occurrences":          schedule.Occurrences,
+			"attempts":             schedule.Attempts,
+			"consecutive_failures": schedule.ConsecutiveFailures,
+			"last_error":           schedule.LastError,
+			"status":               schedule.Status,
+			"updated_at":           schedule.UpdatedAt,
+		}).
+		Where(
+			goqu.C("id").Eq(schedule.ID),
+			goqu.C("status").Eq(domain.ScheduleStatusActive),
+			goqu.C("next_run_at").Eq(leaseUntil),
+		).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ListDue returns active schedules whose next run has come, oldest first
+func (r *MysqlScheduleRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledTransfer, error) {
+	var schedules []domain.ScheduledTransfer
+	err := r.db.From("scheduled_transfers").
+		Where(
+			goqu.C("status").Eq(domain.ScheduleStatusActive),
+			goqu.C("next_run_at").Lte(now),
+		).
+		Order(goqu.C("next_run_at").Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &schedules)
+	if err != nil {
+		return nil, err
+	}
+	for i := range schedules {
+		schedules[i].ApplyCurrency()
+	}
+	return schedules, nil
+}
+
+// Claim pushes next_run_at to leaseUntil if it still equals nextRunAt, so only one worker
+// runs a due schedule. A worker that dies mid-run leaves the schedule due again after the lease.
+func (r *MysqlScheduleRepo) Claim(ctx context.Context, id int64, nextRunAt, leaseUntil time.Time) (bool, error) {
+	result, err := r.db.Update("scheduled_transfers").
+		Set(goqu.Record{"next_run_at": leaseUntil}).
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("status").Eq(domain.ScheduleStatusActive),
+			goqu.C("next_run_at").Eq(nextRunAt),
+		).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *MysqlScheduleRepo) CreateExecution(ctx context.Context, execution *domain.ScheduleExecution) error {
+	return r.CreateExecutionWithTx(ctx, nil, execution)
+}
+
+func (r *MysqlScheduleRepo) CreateExecutionWithTx(ctx context.Context, tx interface{}, execution *domain.ScheduleExecution) error {
+	db, err := txExecutor(r.db, tx)
+	if err != nil {
+		return err
+	}
+	result, err := db.Insert("scheduled_transfer_executions").
+		Rows(goqu.Record{
+			"schedule_id":    execution.ScheduleID,
+			"occurrence_at":  execution.OccurrenceAt,
+			"attempt":        execution.Attempt,
+			"status":         execution.Status,
+			"transaction_id": execution.TransactionID,
+			"error":          execution.Error,
+			"executed_at":    execution.ExecutedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	execution.ID = id
+	return nil
+}
+
+// GetExecutions lists the latest executions of a schedule, newest first
+func (r *MysqlScheduleRepo) GetExecutions(ctx context.Context, scheduleID int64, limit int) ([]domain.ScheduleExecution, error) {
+	var executions []domain.ScheduleExecution
+	err := r.db.From("scheduled_transfer_executions").
+		Where(goqu.C("schedule_id").Eq(scheduleID)).
+		Order(goqu.C("id").Desc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &executions)
+	if err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 	"wallet-api/internal/domain"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 )
 
 // MysqlTransactionRepo handles transaction records
@@ -20,6 +22,38 @@ func NewMysqlTransactionRepository(db *sql.DB) domain.TransactionRepository {
 	return &MysqlTransactionRepo{db: dialect.DB(db)}
 }
 
+// transactionRecord maps a transaction to its insertable columns
+func transactionRecord(transaction *domain.Transaction) goqu.Record {
+	return goqu.Record{
+		"sender_wallet_id":    transaction.SenderWalletID,
+		"receiver_wallet_id":  transaction.ReceiverWalletID,
+		"type":                transaction.Type,
+		"amount":              transaction.Amount,
+		"currency":            transaction.Currency,
+		"counter_amount":      transaction.CounterAmount,
+		"counter_currency":    transaction.CounterCurrency,
+		"fx_rate":             transaction.FxRate,
+		"fx_spread":           transaction.FxSpread,
+		"reversal_of_id":      transaction.ReversalOfID,
+		"reversal_reason":     transaction.ReversalReason,
+		"exchange_for_id":     transaction.ExchangeForID,
+		"batch_id":            transaction.BatchID,
+		"fee_for_id":          transaction.FeeForID,
+		"status":              transaction.Status,
+		"bank_code":           transaction.BankCode,
+		"bank_account_number": transaction.BankAccountNumber,
+		"bank_account_name":   transaction.BankAccountName,
+		"external_reference":  transaction.ExternalReference,
+		"failure_reason":      transaction.FailureReason,
+		"description":         transaction.Description,
+		"client_reference":    transaction.ClientReference,
+		"reference_user_id":   transaction.ReferenceUserID,
+		"metadata":            transaction.Metadata,
+		"created_at":          transaction.CreatedAt,
+		"updated_at":          transaction.UpdatedAt,
+	}
+}
+
 // CreateWithTx creates a transaction record within a database transaction context
 func (r *MysqlTransactionRepo) CreateWithTx(ctx context.Context, tx interface{}, transaction *domain.Transaction) error {
 	// Cast tx to *goqu.TxDatabase
@@ -28,15 +62,9 @@ func (r *MysqlTransactionRepo) CreateWithTx(ctx context.Context, tx interface{},
 		return errors.New("invalid transaction type")
 	}
 
+	transaction.Currency = transaction.Amount.Currency
 	result, err := txDb.Insert("transactions").
-		Rows(goqu.Record{
-			"sender_wallet_id":   transaction.SenderWalletID,
-			"receiver_wallet_id": transaction.ReceiverWalletID,
-			"amount":             transaction.Amount,
-			"status":             transaction.Status,
-			"created_at":         transaction.CreatedAt,
-			"updated_at":         transaction.UpdatedAt,
-		}).
+		Rows(transactionRecord(transaction)).
 		Executor().ExecContext(ctx)
 	if err != nil {
 		return err
@@ -51,15 +79,9 @@ func (r *MysqlTransactionRepo) CreateWithTx(ctx context.Context, tx interface{},
 
 // Create (outside transaction)
 func (r *MysqlTransactionRepo) Create(ctx context.Context, transaction *domain.Transaction) error {
+	transaction.Currency = transaction.Amount.Currency
 	result, err := r.db.Insert("transactions").
-		Rows(goqu.Record{
-			"sender_wallet_id":   transaction.SenderWalletID,
-			"receiver_wallet_id": transaction.ReceiverWalletID,
-			"amount":             transaction.Amount,
-			"status":             transaction.Status,
-			"created_at":         transaction.CreatedAt,
-			"updated_at":         transaction.UpdatedAt,
-		}).
+		Rows(transactionRecord(transaction)).
 		Executor().ExecContext(ctx)
 
 	if err != nil {
@@ -85,17 +107,66 @@ func (r *MysqlTransactionRepo) GetByID(ctx context.Context, id int64) (*domain.T
 	if !found {
 		return nil, nil
 	}
+	transaction.ApplyCurrency()
+	return &transaction, nil
+}
+
+// GetBySenderReferenceWithTx finds the transaction a user sent with a client reference, from any of their wallets
+func (r *MysqlTransactionRepo) GetBySenderReferenceWithTx(ctx context.Context, tx interface{}, senderUserID int64, reference string) (*domain.Transaction, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+
+	var transaction domain.Transaction
+	found, err := txDb.From("transactions").
+		Where(
+			goqu.C("reference_user_id").Eq(senderUserID),
+			goqu.C("client_reference").Eq(reference),
+		).
+		ScanStructContext(ctx, &transaction)
+
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	transaction.ApplyCurrency()
+	return &transaction, nil
+}
+
+// GetByIDForUpdate fetches a transaction and locks its row until txDb ends
+func (r *MysqlTransactionRepo) GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*domain.Transaction, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+
+	var transaction domain.Transaction
+	found, err := txDb.From("transactions").
+		Where(goqu.C("id").Eq(id)).
+		ForUpdate(goqu.Wait).
+		ScanStructContext(ctx, &transaction)
+
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	transaction.ApplyCurrency()
 	return &transaction, nil
 }
 
-func (r *MysqlTransactionRepo) GetByWalletID(ctx context.Context, walletID int64, limit, offset int) ([]domain.Transaction, error) {
+// GetByWalletIDs lists transactions touching any of the given wallets that match filter, newest first
+func (r *MysqlTransactionRepo) GetByWalletIDs(ctx context.Context, walletIDs []int64, filter domain.TransactionFilter, limit, offset int) ([]domain.Transaction, error) {
 	var transactions []domain.Transaction
-	err := r.db.From("transactions").
-		Where(goqu.Or(
-			goqu.C("sender_wallet_id").Eq(walletID),
-			goqu.C("receiver_wallet_id").Eq(walletID),
-		)).
-		Order(goqu.C("created_at").Desc()).
+	if len(walletIDs) == 0 {
+		return transactions, nil
+	}
+	err := r.historyQuery(walletIDs, filter).
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
 		Limit(uint(limit)).
 		Offset(uint(offset)).
 		ScanStructsContext(ctx, &transactions)
@@ -103,9 +174,154 @@ func (r *MysqlTransactionRepo) GetByWalletID(ctx context.Context, walletID int64
 	if err != nil {
 		return nil, err
 	}
+	for i := range transactions {
+		transactions[i].ApplyCurrency()
+	}
+	return transactions, nil
+}
+
+// CountByWalletIDs counts the transactions GetByWalletIDs pages through
+func (r *MysqlTransactionRepo) CountByWalletIDs(ctx context.Context, walletIDs []int64, filter domain.TransactionFilter) (uint64, error) {
+	if len(walletIDs) == 0 {
+		return 0, nil
+	}
+	var total uint64
+	_, err := r.historyQuery(walletIDs, filter).
+		Select(goqu.COUNT("*")).
+		ScanValContext(ctx, &total)
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// StreamByWalletIDs runs the GetByWalletIDs query without paging and hands each row to fn as it
+// is read
+func (r *MysqlTransactionRepo) StreamByWalletIDs(ctx context.Context, walletIDs []int64, filter domain.TransactionFilter, fn func(domain.Transaction) error) error {
+	if len(walletIDs) == 0 {
+		return nil
+	}
+	scanner, err := r.historyQuery(walletIDs, filter).
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
+		Executor().ScannerContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer scanner.Close()
+
+	for scanner.Next() {
+		var transaction domain.Transaction
+		if err := scanner.ScanStruct(&transaction); err != nil {
+			return err
+		}
+		transaction.ApplyCurrency()
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// GetByWalletIDsAt reads up to limit transactions on one side of cursor, newest first. Unlike
+// an offset, the position stays put when new transactions land between two pages.
+func (r *MysqlTransactionRepo) GetByWalletIDsAt(ctx context.Context, walletIDs []int64, filter domain.TransactionFilter, cursor *domain.HistoryCursor, limit int) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	if len(walletIDs) == 0 {
+		return transactions, nil
+	}
+
+	query := r.historyQuery(walletIDs, filter)
+	switch {
+	case cursor == nil:
+		query = query.Order(goqu.C("created_at").Desc(), goqu.C("id").Desc())
+	case cursor.Backward:
+		// Walk up from the cursor and flip the rows back afterwards
+		query = query.
+			Where(goqu.Or(
+				goqu.C("created_at").Gt(cursor.CreatedAt),
+				goqu.And(goqu.C("created_at").Eq(cursor.CreatedAt), goqu.C("id").Gt(cursor.ID)),
+			)).
+			Order(goqu.C("created_at").Asc(), goqu.C("id").Asc())
+	default:
+		query = query.
+			Where(goqu.Or(
+				goqu.C("created_at").Lt(cursor.CreatedAt),
+				goqu.And(goqu.C("created_at").Eq(cursor.CreatedAt), goqu.C("id").Lt(cursor.ID)),
+			)).
+			Order(goqu.C("created_at").Desc(), goqu.C("id").Desc())
+	}
+	err := query.Limit(uint(limit)).ScanStructsContext(ctx, &transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+			transactions[i], transactions[j] = transactions[j], transactions[i]
+		}
+	}
+	for i := range transactions {
+		transactions[i].ApplyCurrency()
+	}
 	return transactions, nil
 }
 
+// historyQuery selects the transactions of walletIDs that match filter, leaving the order to
+// the caller
+func (r *MysqlTransactionRepo) historyQuery(walletIDs []int64, filter domain.TransactionFilter) *goqu.SelectDataset {
+	query := r.db.From("transactions")
+	switch filter.Direction {
+	case domain.TransactionDirectionOut:
+		query = query.Where(goqu.C("sender_wallet_id").In(walletIDs))
+	case domain.TransactionDirectionIn:
+		// Moves between two of the wallets read as outgoing, see Transaction.DirectionFor
+		query = query.Where(
+			goqu.C("receiver_wallet_id").In(walletIDs),
+			goqu.Or(
+				goqu.C("sender_wallet_id").IsNull(),
+				goqu.C("sender_wallet_id").NotIn(walletIDs),
+			),
+		)
+	default:
+		query = query.Where(goqu.Or(
+			goqu.C("sender_wallet_id").In(walletIDs),
+			goqu.C("receiver_wallet_id").In(walletIDs),
+		))
+	}
+
+	if filter.From != nil {
+		query = query.Where(goqu.C("created_at").Gte(*filter.From))
+	}
+	if filter.To != nil {
+		query = query.Where(goqu.C("created_at").Lt(*filter.To))
+	}
+	if filter.Status != "" {
+		query = query.Where(goqu.C("status").Eq(filter.Status))
+	}
+	if filter.Type != "" {
+		query = query.Where(goqu.C("type").Eq(filter.Type))
+	}
+	if filter.Reference != "" {
+		query = query.Where(goqu.C("client_reference").Eq(filter.Reference))
+	}
+	if filter.CounterpartyUserID != 0 {
+		counterpartyWallets := r.db.From("wallets").
+			Select("id").
+			Where(goqu.C("user_id").Eq(filter.CounterpartyUserID))
+		query = query.Where(goqu.Or(
+			goqu.C("sender_wallet_id").In(counterpartyWallets),
+			goqu.C("receiver_wallet_id").In(counterpartyWallets),
+		))
+	}
+	if filter.MinAmount != nil {
+		query = query.Where(goqu.C("amount").Gte(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where(goqu.C("amount").Lte(*filter.MaxAmount))
+	}
+	return query
+}
+
 func (r *MysqlTransactionRepo) UpdateStatus(ctx context.Context, id int64, status domain.TransactionStatus) error {
 	_, err := r.db.Update("transactions").
 		Set(goqu.Record{"status": status}).
@@ -113,3 +329,196 @@ func (r *MysqlTransactionRepo) UpdateStatus(ctx context.Context, id int64, statu
 		Executor().ExecContext(ctx)
 	return err
 }
+
+// UpdateWithTx persists the status and settlement fields of a transaction
+func (r *MysqlTransactionRepo) UpdateWithTx(ctx context.Context, tx interface{}, transaction *domain.Transaction) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+
+	_, err := txDb.Update("transactions").
+		Set(goqu.Record{
+			"status":             transaction.Status,
+			"external_reference": transaction.ExternalReference,
+			"failure_reason":     transaction.FailureReason,
+			"updated_at":         transaction.UpdatedAt,
+		}).
+		Where(goqu.C("id").Eq(transaction.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// SumReversalsWithTx adds up the successful refunds already issued against a transaction
+func (r *MysqlTransactionRepo) SumReversalsWithTx(ctx context.Context, tx interface{}, originalID int64) (domain.Money, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return domain.Money{}, errors.New("invalid transaction type")
+	}
+
+	var total domain.Money
+	_, err := txDb.From("transactions").
+		Select(goqu.L("COALESCE(SUM(amount), 0)")).
+		Where(
+			goqu.C("reversal_of_id").Eq(originalID),
+			goqu.C("status").Eq(domain.TransactionStatusSuccess),
+		).
+		ScanValContext(ctx, &total)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return total, nil
+}
+
+// ReplayBalances recomputes wallet balances from the transactions table. Successful rows move
+// money; pending withdrawals already moved their funds to the treasury wallet, so they count on
+// both sides too. Cross-currency transfers credit the receiver with the counter amount.
+func (r *MysqlTransactionRepo) ReplayBalances(ctx context.Context, tx interface{}, walletIDs []int64) (map[int64]domain.Money, error) {
+	var db GoquExecutor = r.db
+	if tx != nil {
+		txDb, ok := tx.(*goqu.TxDatabase)
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		db = txDb
+	}
+	return replayBalances(ctx, db, walletIDs, time.Time{})
+}
+
+// ReplayBalancesBefore is ReplayBalances as of before, counting only rows created earlier
+func (r *MysqlTransactionRepo) ReplayBalancesBefore(ctx context.Context, walletIDs []int64, before time.Time) (map[int64]domain.Money, error) {
+	return replayBalances(ctx, r.db, walletIDs, before)
+}
+
+// replayBalances sums the moved funds per wallet, only of rows created before a non-zero before
+func replayBalances(ctx context.Context, db GoquExecutor, walletIDs []int64, before time.Time) (map[int64]domain.Money, error) {
+	credits := db.From("transactions").
+		Select(
+			goqu.C("receiver_wallet_id").As("wallet_id"),
+			goqu.L("COALESCE(counter_amount, amount)").As("delta"),
+		).
+		Where(
+			goqu.C("receiver_wallet_id").IsNotNull(),
+			movedFunds(),
+		)
+	debits := db.From("transactions").
+		Select(
+			goqu.C("sender_wallet_id").As("wallet_id"),
+			goqu.L("-amount").As("delta"),
+		).
+		Where(
+			goqu.C("sender_wallet_id").IsNotNull(),
+			movedFunds(),
+		)
+	if len(walletIDs) > 0 {
+		credits = credits.Where(goqu.C("receiver_wallet_id").In(walletIDs))
+		debits = debits.Where(goqu.C("sender_wallet_id").In(walletIDs))
+	}
+	if !before.IsZero() {
+		credits = credits.Where(goqu.C("created_at").Lt(before))
+		debits = debits.Where(goqu.C("created_at").Lt(before))
+	}
+
+	var rows []struct {
+		WalletID int64        `db:"wallet_id"`
+		Balance  domain.Money `db:"balance"`
+	}
+	err := db.From(credits.UnionAll(debits).As("movements")).
+		Select(
+			goqu.C("wallet_id"),
+			goqu.SUM("delta").As("balance"),
+		).
+		GroupBy(goqu.C("wallet_id")).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int64]domain.Money, len(rows))
+	for _, row := range rows {
+		balances[row.WalletID] = row.Balance
+	}
+	return balances, nil
+}
+
+// GetMovementsBetween lists the rows that moved a wallet balance in [from, to), oldest first
+func (r *MysqlTransactionRepo) GetMovementsBetween(ctx context.Context, walletID int64, from, to time.Time) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	err := r.db.From("transactions").
+		Where(
+			goqu.Or(
+				goqu.C("sender_wallet_id").Eq(walletID),
+				goqu.C("receiver_wallet_id").Eq(walletID),
+			),
+			goqu.C("created_at").Gte(from),
+			goqu.C("created_at").Lt(to),
+			movedFunds(),
+		).
+		Order(goqu.C("created_at").Asc(), goqu.C("id").Asc()).
+		ScanStructsContext(ctx, &transactions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transactions {
+		transactions[i].ApplyCurrency()
+	}
+	return transactions, nil
+}
+
+// movedFunds matches the rows whose amounts are reflected in wallet balances
+func movedFunds() exp.Expression {
+	return goqu.Or(
+		goqu.C("status").Eq(domain.TransactionStatusSuccess),
+		goqu.And(
+			goqu.C("type").Eq(domain.TransactionTypeWithdrawal),
+			goqu.C("status").Eq(domain.TransactionStatusPending),
+		),
+	)
+}
+
+// OutgoingUsageWithTx totals the transfers, withdrawals, captures and active holds sent from any
+// wallet of a user in currency since monthStart, splitting out those since dayStart. Pending rows
+// count so in-flight withdrawals are not free.
+func (r *MysqlTransactionRepo) OutgoingUsageWithTx(ctx context.Context, tx interface{}, userID int64, currency string, dayStart, monthStart time.Time) (domain.TransferUsage, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return domain.TransferUsage{}, errors.New("invalid transaction type")
+	}
+
+	walletIDs := txDb.From("wallets").
+		Select("id").
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("currency").Eq(currency),
+		)
+	moved := txDb.From("transactions").
+		Select("amount", "created_at").
+		Where(
+			goqu.C("sender_wallet_id").In(walletIDs),
+			goqu.C("created_at").Gte(monthStart),
+			goqu.C("type").In(domain.TransactionTypeTransfer, domain.TransactionTypeWithdrawal, domain.TransactionTypeCapture),
+			goqu.C("status").In(domain.TransactionStatusSuccess, domain.TransactionStatusPending),
+		)
+	// Active holds count until they are captured or released
+	held := txDb.From("holds").
+		Select("amount", "created_at").
+		Where(
+			goqu.C("wallet_id").In(walletIDs),
+			goqu.C("created_at").Gte(monthStart),
+			goqu.C("status").Eq(domain.HoldStatusActive),
+		)
+
+	var usage domain.TransferUsage
+	_, err := txDb.From(moved.UnionAll(held).As("outgoing")).
+		Select(
+			goqu.L("COALESCE(SUM(CASE WHEN created_at >= ? THEN amount END), 0)", dayStart).As("daily_amount"),
+			goqu.L("COUNT(CASE WHEN created_at >= ? THEN 1 END)", dayStart).As("daily_count"),
+			goqu.L("COALESCE(SUM(amount), 0)").As("monthly_amount"),
+			goqu.COUNT("*").As("monthly_count"),
+		).
+		ScanStructContext(ctx, &usage)
+	if err != nil {
+		return domain.TransferUsage{}, err
+	}
+	return usage, nil
+}
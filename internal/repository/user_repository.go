@@ -29,6 +29,8 @@ func (r *MysqlUserRepository) Create(ctx context.Context, user *domain.User) err
 	ds := r.db.Insert(UserTable).Rows(goqu.Record{
 		"name":       user.Name,
 		"email":      user.Email,
+		"phone":      user.Phone,
+		"handle":     user.Handle,
 		"password":   user.Password,
 		"created_at": user.CreatedAt,
 		"updated_at": user.UpdatedAt,
@@ -71,10 +73,37 @@ func (r *MysqlUserRepository) GetByEmail(ctx context.Context, email string) (*do
 	return &user, nil
 }
 
+// GetByPhone retrieves a user by their E.164 phone number
+func (r *MysqlUserRepository) GetByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	return r.getBy(ctx, "phone", phone)
+}
+
+// GetByHandle retrieves a user by their public handle
+func (r *MysqlUserRepository) GetByHandle(ctx context.Context, handle string) (*domain.User, error) {
+	return r.getBy(ctx, "handle", handle)
+}
+
+func (r *MysqlUserRepository) getBy(ctx context.Context, column string, value string) (*domain.User, error) {
+	var user domain.User
+	found, err := r.db.From(UserTable).
+		Where(goqu.C(column).Eq(value)).
+		ScanStructContext(ctx, &user)
+
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &user, nil
+}
+
 func (r *MysqlUserRepository) Update(ctx context.Context, user *domain.User) error {
 	_, err := r.db.Update(UserTable).
 		Set(goqu.Record{
 			"name":     user.Name,
+			"phone":    user.Phone,
+			"handle":   user.Handle,
 			"password": user.Password,
 		}).
 		Where(goqu.C("id").Eq(user.ID)).
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"wallet-api/internal/domain"
 
 	"github.com/doug-martin/goqu/v9"
@@ -27,6 +28,18 @@ type GoquExecutor interface {
 	Delete(table interface{}) *goqu.DeleteDataset
 }
 
+// txExecutor runs on tx when one is given and on db otherwise
+func txExecutor(db *goqu.Database, tx interface{}) (GoquExecutor, error) {
+	if tx == nil {
+		return db, nil
+	}
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+	return txDb, nil
+}
+
 // Helper to get goqu executor
 func getDb(r *MysqlWalletRepo, tx interface{}) GoquExecutor {
 	if tx != nil {
@@ -43,12 +56,23 @@ func (r *MysqlWalletRepo) Create(ctx context.Context, wallet *domain.Wallet) err
 
 func (r *MysqlWalletRepo) CreateWithTx(ctx context.Context, tx interface{}, wallet *domain.Wallet) error {
 	db := getDb(r, tx)
+	if wallet.Status == "" {
+		wallet.Status = domain.WalletStatusActive
+	}
+	if wallet.Name == "" {
+		wallet.Name = domain.PrimaryPocketName
+	}
 	result, err := db.Insert("wallets").
 		Rows(goqu.Record{
-			"user_id":    wallet.UserID,
-			"balance":    wallet.Balance,
-			"created_at": wallet.CreatedAt,
-			"updated_at": wallet.UpdatedAt,
+			"user_id":     wallet.UserID,
+			"system_code": wallet.SystemCode,
+			"currency":    wallet.Currency,
+			"name":        wallet.Name,
+			"is_primary":  wallet.IsPrimary,
+			"balance":     wallet.Balance,
+			"status":      wallet.Status,
+			"created_at":  wallet.CreatedAt,
+			"updated_at":  wallet.UpdatedAt,
 		}).
 		Executor().ExecContext(ctx)
 
@@ -74,13 +98,50 @@ func (r *MysqlWalletRepo) GetByID(ctx context.Context, id int64) (*domain.Wallet
 	if !found {
 		return nil, nil
 	}
+	wallet.ApplyCurrency()
 	return &wallet, nil
 }
 
-func (r *MysqlWalletRepo) GetByUserID(ctx context.Context, userID int64) (*domain.Wallet, error) {
+// GetByUserID returns every wallet of a user, archived pockets included, primary wallets first
+func (r *MysqlWalletRepo) GetByUserID(ctx context.Context, userID int64) ([]domain.Wallet, error) {
+	var wallets []domain.Wallet
+	err := r.db.From("wallets").
+		Where(goqu.C("user_id").Eq(userID)).
+		Order(goqu.C("is_primary").Desc(), goqu.C("id").Asc()).
+		ScanStructsContext(ctx, &wallets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range wallets {
+		wallets[i].ApplyCurrency()
+	}
+	return wallets, nil
+}
+
+// List returns every wallet ordered by id
+func (r *MysqlWalletRepo) List(ctx context.Context) ([]domain.Wallet, error) {
+	var wallets []domain.Wallet
+	err := r.db.From("wallets").
+		Order(goqu.C("id").Asc()).
+		ScanStructsContext(ctx, &wallets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range wallets {
+		wallets[i].ApplyCurrency()
+	}
+	return wallets, nil
+}
+
+// GetByUserIDAndCurrency returns the primary wallet of a user in currency
+func (r *MysqlWalletRepo) GetByUserIDAndCurrency(ctx context.Context, userID int64, currency string) (*domain.Wallet, error) {
 	var wallet domain.Wallet
 	found, err := r.db.From("wallets").
-		Where(goqu.C("user_id").Eq(userID)).
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("currency").Eq(currency),
+			goqu.C("is_primary").IsTrue(),
+		).
 		ScanStructContext(ctx, &wallet)
 	if err != nil {
 		return nil, err
@@ -88,10 +149,11 @@ func (r *MysqlWalletRepo) GetByUserID(ctx context.Context, userID int64) (*domai
 	if !found {
 		return nil, nil
 	}
+	wallet.ApplyCurrency()
 	return &wallet, nil
 }
 
-func (r *MysqlWalletRepo) GetWalletForUpdate(ctx context.Context, tx interface{}, userID int64) (*domain.Wallet, error) {
+func (r *MysqlWalletRepo) GetWalletForUpdate(ctx context.Context, tx interface{}, userID int64, currency string) (*domain.Wallet, error) {
 	db := getDb(r, tx)
 	var wallet domain.Wallet
 
@@ -99,7 +161,11 @@ func (r *MysqlWalletRepo) GetWalletForUpdate(ctx context.Context, tx interface{}
 	// If generic Interface doesn't support ForUpdate directly in check (it does in goqu), we use From
 
 	found, err := db.From("wallets").
-		Where(goqu.C("user_id").Eq(userID)).
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.C("currency").Eq(currency),
+			goqu.C("is_primary").IsTrue(),
+		).
 		ForUpdate(goqu.Wait).
 		ScanStructContext(ctx, &wallet)
 
@@ -109,10 +175,53 @@ func (r *MysqlWalletRepo) GetWalletForUpdate(ctx context.Context, tx interface{}
 	if !found {
 		return nil, nil // Return nil if not found, let service handle
 	}
+	wallet.ApplyCurrency()
+	return &wallet, nil
+}
+
+// GetByIDForUpdate fetches a wallet by its ID and locks the row
+func (r *MysqlWalletRepo) GetByIDForUpdate(ctx context.Context, tx interface{}, id int64) (*domain.Wallet, error) {
+	db := getDb(r, tx)
+	var wallet domain.Wallet
+
+	found, err := db.From("wallets").
+		Where(goqu.C("id").Eq(id)).
+		ForUpdate(goqu.Wait).
+		ScanStructContext(ctx, &wallet)
+
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	wallet.ApplyCurrency()
+	return &wallet, nil
+}
+
+// GetSystemWallet fetches the system wallet of a currency without locking it
+func (r *MysqlWalletRepo) GetSystemWallet(ctx context.Context, tx interface{}, code domain.SystemWallet, currency string) (*domain.Wallet, error) {
+	db := getDb(r, tx)
+	var wallet domain.Wallet
+
+	found, err := db.From("wallets").
+		Where(
+			goqu.C("system_code").Eq(code),
+			goqu.C("currency").Eq(currency),
+		).
+		ScanStructContext(ctx, &wallet)
+
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	wallet.ApplyCurrency()
 	return &wallet, nil
 }
 
-func (r *MysqlWalletRepo) UpdateBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, newBalance float64) error {
+func (r *MysqlWalletRepo) UpdateBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, newBalance domain.Money) error {
 	db := getDb(r, tx)
 	_, err := db.Update("wallets").
 		Set(goqu.Record{
@@ -124,7 +233,50 @@ func (r *MysqlWalletRepo) UpdateBalanceWithTx(ctx context.Context, tx interface{
 	return err
 }
 
-func (r *MysqlWalletRepo) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+// UpdateStatusWithTx persists the status of a wallet and who changed it
+func (r *MysqlWalletRepo) UpdateStatusWithTx(ctx context.Context, tx interface{}, wallet *domain.Wallet) error {
+	db := getDb(r, tx)
+	_, err := db.Update("wallets").
+		Set(goqu.Record{
+			"status":            wallet.Status,
+			"status_reason":     wallet.StatusReason,
+			"status_changed_by": wallet.StatusChangedBy,
+			"status_changed_at": wallet.StatusChangedAt,
+			"updated_at":        goqu.L("NOW()"),
+		}).
+		Where(goqu.C("id").Eq(wallet.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// UpdatePocketWithTx persists the name of a pocket and whether it is archived
+func (r *MysqlWalletRepo) UpdatePocketWithTx(ctx context.Context, tx interface{}, wallet *domain.Wallet) error {
+	db := getDb(r, tx)
+	_, err := db.Update("wallets").
+		Set(goqu.Record{
+			"name":        wallet.Name,
+			"archived_at": wallet.ArchivedAt,
+			"updated_at":  goqu.L("NOW()"),
+		}).
+		Where(goqu.C("id").Eq(wallet.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// UpdateHeldBalanceWithTx sets the amount reserved by active holds
+func (r *MysqlWalletRepo) UpdateHeldBalanceWithTx(ctx context.Context, tx interface{}, walletID int64, held domain.Money) error {
+	db := getDb(r, tx)
+	_, err := db.Update("wallets").
+		Set(goqu.Record{
+			"held_balance": held,
+			"updated_at":   goqu.L("NOW()"),
+		}).
+		Where(goqu.C("id").Eq(walletID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (r *MysqlWalletRepo) UpdateBalance(ctx context.Context, id int64, amount domain.Money) error {
 	// Simple non-transactional update (not recommended for financial ops usually, but implemented for interface)
 	_, err := r.db.Update("wallets").
 		Set(goqu.Record{"balance": amount}).
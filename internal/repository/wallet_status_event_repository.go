@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MysqlWalletStatusEventRepo handles the history of wallet status changes
+type MysqlWalletStatusEventRepo struct {
+	db *goqu.Database
+}
+
+// NewMysqlWalletStatusEventRepository creates a new wallet status event repository
+func NewMysqlWalletStatusEventRepository(db *sql.DB) domain.WalletStatusEventRepository {
+	dialect := goqu.Dialect("mysql")
+	return &MysqlWalletStatusEventRepo{db: dialect.DB(db)}
+}
+
+func (r *MysqlWalletStatusEventRepo) CreateWithTx(ctx context.Context, tx interface{}, event *domain.WalletStatusEvent) error {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return errors.New("invalid transaction type")
+	}
+
+	result, err := txDb.Insert("wallet_status_events").
+		Rows(goqu.Record{
+			"wallet_id":   event.WalletID,
+			"from_status": event.FromStatus,
+			"to_status":   event.ToStatus,
+			"reason":      event.Reason,
+			"actor":       event.Actor,
+			"created_at":  event.CreatedAt,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.ID = id
+	return nil
+}
+
+// GetByWalletID lists the status changes of a wallet, newest first
+func (r *MysqlWalletStatusEventRepo) GetByWalletID(ctx context.Context, walletID int64) ([]domain.WalletStatusEvent, error) {
+	var events []domain.WalletStatusEvent
+	err := r.db.From("wallet_status_events").
+		Where(goqu.C("wallet_id").Eq(walletID)).
+		Order(goqu.C("id").Desc()).
+		ScanStructsContext(ctx, &events)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListSince lists the status changes after afterID in the order they happened
+func (r *MysqlWalletStatusEventRepo) ListSince(ctx context.Context, afterID int64, limit int) ([]domain.WalletStatusEvent, error) {
+	var events []domain.WalletStatusEvent
+	err := r.db.From("wallet_status_events").
+		Where(goqu.C("id").Gt(afterID)).
+		Order(goqu.C("id").Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &events)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
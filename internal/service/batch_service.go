@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/utils"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// MaxBatchLegs caps the number of recipients of one batch transfer
+const MaxBatchLegs = 100
+
+// BatchTransfer pays several recipients from the sender wallet in one database transaction.
+// Every leg is recorded as its own transfer sharing a batch id and is charged the transfer fee
+// like a single transfer; if any leg cannot be applied nothing is.
+func (s *DefaultWalletService) BatchTransfer(ctx context.Context, senderUserID int64, legs []domain.TransferLeg) (*domain.BatchTransfer, error) {
+	// 1. Validate every leg before touching the database
+	if len(legs) == 0 {
+		return nil, domain.ErrEmptyBatch
+	}
+	if len(legs) > MaxBatchLegs {
+		return nil, fmt.Errorf("batch cannot contain more than %d transfers", MaxBatchLegs)
+	}
+
+	var total, totalFee, largest domain.Money
+	fees := make([]domain.Money, len(legs))
+	for i := range legs {
+		amount, err := validateAmount(legs[i].Amount)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i+1, err)
+		}
+		if legs[i].ReceiverUserID == senderUserID {
+			return nil, fmt.Errorf("transfer %d: cannot transfer to self", i+1)
+		}
+		if i == 0 {
+			total = domain.NewMoney(0, amount.Currency)
+			totalFee = domain.NewMoney(0, amount.Currency)
+		}
+		if err := total.SameCurrency(amount); err != nil {
+			return nil, fmt.Errorf("transfer %d: every transfer of a batch must use the same currency: %w", i+1, err)
+		}
+		legs[i].Amount = amount
+		total = total.Add(amount)
+		if largest.LessThan(amount) {
+			largest = amount
+		}
+		if fees[i], err = s.feeFor(ctx, domain.FeeOperationTransfer, amount); err != nil {
+			return nil, err
+		}
+		totalFee = totalFee.Add(fees[i])
+	}
+	currency := total.Currency
+
+	// 2. Resolve the wallets of everyone involved
+	senderWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, senderUserID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sender wallet: %w", err)
+	}
+	if senderWallet == nil {
+		return nil, errors.New("sender wallet not found")
+	}
+	walletIDs := []int64{senderWallet.ID}
+	receiverWalletIDs := make([]int64, len(legs))
+	for i, leg := range legs {
+		receiverWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, leg.ReceiverUserID, currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+		}
+		if receiverWallet == nil {
+			return nil, fmt.Errorf("transfer %d: %w", i+1, s.missingReceiverWallet(ctx, leg.ReceiverUserID, currency))
+		}
+		receiverWalletIDs[i] = receiverWallet.ID
+		walletIDs = append(walletIDs, receiverWallet.ID)
+	}
+
+	batchID := utils.GenerateRandomString(16)
+	if batchID == "" {
+		return nil, errors.New("failed to generate batch id")
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	var feeWalletID int64
+	if totalFee.IsPositive() {
+		feeWallet, err := s.systemWallet(ctx, txDb, domain.SystemWalletFees, currency)
+		if err != nil {
+			return nil, err
+		}
+		feeWalletID = feeWallet.ID
+		walletIDs = append(walletIDs, feeWallet.ID)
+	}
+
+	// 3. Lock every wallet in id order and check the sender can cover the whole batch and its
+	// fees within limits
+	wallets, err := s.lockWallets(ctx, txDb, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	sender := wallets[senderWallet.ID]
+	for i := range legs {
+		if err := checkMovement(sender, wallets[receiverWalletIDs[i]]); err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i+1, err)
+		}
+	}
+	if sender.Available().LessThan(total.Add(totalFee)) {
+		return nil, domain.ErrInsufficientBalance
+	}
+	if err := s.checkLimits(ctx, txDb, sender, largest, total, len(legs)); err != nil {
+		return nil, err
+	}
+
+	// 4. Apply each leg in memory and record it with its own postings
+	now := time.Now()
+	result := &domain.BatchTransfer{
+		BatchID:      batchID,
+		Total:        total,
+		TotalFee:     totalFee,
+		Transactions: make([]domain.Transaction, 0, len(legs)),
+	}
+	for i, leg := range legs {
+		receiver := wallets[receiverWalletIDs[i]]
+		sender.Balance = sender.Balance.Sub(leg.Amount)
+		receiver.Balance = receiver.Balance.Add(leg.Amount)
+
+		transaction := domain.Transaction{
+			SenderWalletID:   &sender.ID,
+			ReceiverWalletID: &receiver.ID,
+			Type:             domain.TransactionTypeTransfer,
+			Amount:           leg.Amount,
+			BatchID:          &batchID,
+			Status:           domain.TransactionStatusSuccess,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := s.tRepo.CreateWithTx(ctx, txDb, &transaction); err != nil {
+			return nil, fmt.Errorf("failed to create transaction record: %w", err)
+		}
+		if err := s.postJournal(ctx, txDb, &transaction.ID,
+			walletPosting(sender, domain.LedgerDebit, leg.Amount),
+			walletPosting(receiver, domain.LedgerCredit, leg.Amount),
+		); err != nil {
+			return nil, err
+		}
+		if fees[i].IsPositive() {
+			if _, err := s.chargeFee(ctx, txDb, sender, wallets[feeWalletID], fees[i], transaction.ID); err != nil {
+				return nil, fmt.Errorf("transfer %d: %w", i+1, err)
+			}
+		}
+		transaction.Fee = &fees[i]
+		result.Transactions = append(result.Transactions, transaction)
+	}
+
+	// 5. Write the final balance of every wallet
+	for _, wallet := range wallets {
+		if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, wallet.ID, wallet.Balance); err != nil {
+			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// DefaultFeeService prices operations from the fee rules table
+type DefaultFeeService struct {
+	repo domain.FeeRuleRepository
+}
+
+// Ensure interface compliance
+var _ domain.FeeService = &DefaultFeeService{}
+
+func NewFeeService(repo domain.FeeRuleRepository) domain.FeeService {
+	return &DefaultFeeService{repo: repo}
+}
+
+// Calculate returns the fee of an operation, zero when no rule covers the amount
+func (s *DefaultFeeService) Calculate(ctx context.Context, operation domain.FeeOperation, amount domain.Money) (domain.Money, error) {
+	rule, err := s.repo.Match(ctx, operation, amount)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("failed to fetch fee rule: %w", err)
+	}
+	if rule == nil {
+		return domain.NewMoney(0, amount.Currency), nil
+	}
+	return rule.Apply(amount), nil
+}
+
+// Quote prices an operation without running it
+func (s *DefaultFeeService) Quote(ctx context.Context, operation domain.FeeOperation, amount domain.Money) (*domain.FeeQuote, error) {
+	if err := validateFeeOperation(operation); err != nil {
+		return nil, err
+	}
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.Match(ctx, operation, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee rule: %w", err)
+	}
+	quote := &domain.FeeQuote{
+		Operation: operation,
+		Amount:    amount,
+		Fee:       domain.NewMoney(0, amount.Currency),
+	}
+	if rule != nil {
+		quote.Fee = rule.Apply(amount)
+		quote.RuleID = &rule.ID
+	}
+
+	switch operation {
+	case domain.FeeOperationTopUp:
+		if quote.Fee.IsPositive() && !quote.Fee.LessThan(amount) {
+			return nil, domain.ErrFeeExceedsAmount
+		}
+		quote.Total = amount.Sub(quote.Fee)
+	default:
+		quote.Total = amount.Add(quote.Fee)
+	}
+	return quote, nil
+}
+
+func (s *DefaultFeeService) ListRules(ctx context.Context) ([]domain.FeeRule, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *DefaultFeeService) SetRule(ctx context.Context, rule *domain.FeeRule) error {
+	if err := validateFeeOperation(rule.Operation); err != nil {
+		return err
+	}
+	currency, err := domain.NormalizeCurrency(rule.Currency)
+	if err != nil {
+		return err
+	}
+	rule.Currency = currency
+	for _, m := range []*domain.Money{&rule.MinAmount, &rule.FlatFee, rule.MinFee, rule.MaxFee} {
+		if m == nil {
+			continue
+		}
+		m.Currency = currency
+		if m.IsNegative() {
+			return errors.New("fee amounts cannot be negative")
+		}
+	}
+	if rule.PercentBps < 0 || rule.PercentBps > 10_000 {
+		return errors.New("percent_bps must be between 0 and 10000")
+	}
+	if rule.MinFee != nil && rule.MaxFee != nil && rule.MaxFee.LessThan(*rule.MinFee) {
+		return errors.New("max_fee cannot be lower than min_fee")
+	}
+	return s.repo.Upsert(ctx, rule)
+}
+
+func (s *DefaultFeeService) DeleteRule(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func validateFeeOperation(operation domain.FeeOperation) error {
+	switch operation {
+	case domain.FeeOperationTransfer, domain.FeeOperationTopUp:
+		return nil
+	}
+	return fmt.Errorf("unknown fee operation %q", operation)
+}
+
+// feeFor prices an operation, zero when no fee calculator is configured
+func (s *DefaultWalletService) feeFor(ctx context.Context, operation domain.FeeOperation, amount domain.Money) (domain.Money, error) {
+	if s.fees == nil {
+		return domain.NewMoney(0, amount.Currency), nil
+	}
+	return s.fees.Calculate(ctx, operation, amount)
+}
+
+// chargeFee moves fee from payer into the fees system wallet as a fee transaction linked to the
+// transaction it was charged for. Both wallets must already be locked by txDb.
+func (s *DefaultWalletService) chargeFee(ctx context.Context, txDb *goqu.TxDatabase, payer, feeWallet *domain.Wallet, fee domain.Money, forID int64) (*domain.Transaction, error) {
+	newPayerBalance := payer.Balance.Sub(fee)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, payer.ID, newPayerBalance); err != nil {
+		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	payer.Balance = newPayerBalance
+
+	newFeeBalance := feeWallet.Balance.Add(fee)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, feeWallet.ID, newFeeBalance); err != nil {
+		return nil, fmt.Errorf("failed to update fees wallet balance: %w", err)
+	}
+	feeWallet.Balance = newFeeBalance
+
+	now := time.Now()
+	transaction := &domain.Transaction{
+		SenderWalletID:   &payer.ID,
+		ReceiverWalletID: &feeWallet.ID,
+		Type:             domain.TransactionTypeFee,
+		Amount:           fee,
+		FeeForID:         &forID,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create fee transaction: %w", err)
+	}
+
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(payer, domain.LedgerDebit, fee),
+		walletPosting(feeWallet, domain.LedgerCredit, fee),
+	); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/utils"
+)
+
+// DefaultFXQuoteTTL is how long a quote can be used when no TTL is configured
+const DefaultFXQuoteTTL = time.Minute
+
+// DefaultFXService issues quotes from the locally stored rate table
+type DefaultFXService struct {
+	repo     domain.FXRepository
+	quoteTTL time.Duration
+}
+
+// Ensure interface compliance
+var _ domain.FXService = &DefaultFXService{}
+
+func NewFXService(repo domain.FXRepository, quoteTTL time.Duration) domain.FXService {
+	if quoteTTL <= 0 {
+		quoteTTL = DefaultFXQuoteTTL
+	}
+	return &DefaultFXService{repo: repo, quoteTTL: quoteTTL}
+}
+
+func (s *DefaultFXService) ListRates(ctx context.Context) ([]domain.FXRate, error) {
+	return s.repo.ListRates(ctx)
+}
+
+func (s *DefaultFXService) SetRate(ctx context.Context, rate *domain.FXRate) error {
+	if err := normalizeFXRate(rate); err != nil {
+		return err
+	}
+	return s.repo.UpsertRate(ctx, rate)
+}
+
+// ImportRatesCSV reads base_currency,quote_currency,rate[,spread_bps] rows. A header row is
+// skipped. Every row is validated before any rate is written.
+func (s *DefaultFXService) ImportRatesCSV(ctx context.Context, r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	var rates []domain.FXRate
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "base_currency") {
+			continue
+		}
+		if len(record) < 3 || len(record) > 4 {
+			return 0, fmt.Errorf("line %d: expected base_currency,quote_currency,rate[,spread_bps]", i+1)
+		}
+
+		rate := domain.FXRate{BaseCurrency: record[0], QuoteCurrency: record[1]}
+		if rate.Rate, err = domain.ParseRate(record[2]); err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(record) == 4 && strings.TrimSpace(record[3]) != "" {
+			if rate.SpreadBps, err = strconv.Atoi(strings.TrimSpace(record[3])); err != nil {
+				return 0, fmt.Errorf("line %d: invalid spread_bps: %w", i+1, err)
+			}
+		}
+		if err := normalizeFXRate(&rate); err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	for i := range rates {
+		if err := s.repo.UpsertRate(ctx, &rates[i]); err != nil {
+			return i, fmt.Errorf("failed to store %s/%s: %w", rates[i].BaseCurrency, rates[i].QuoteCurrency, err)
+		}
+	}
+	return len(rates), nil
+}
+
+// CreateQuote prices source in targetCurrency and stores the offer until it expires
+func (s *DefaultFXService) CreateQuote(ctx context.Context, userID int64, source domain.Money, targetCurrency string) (*domain.FXQuote, error) {
+	source, err := validateAmount(source)
+	if err != nil {
+		return nil, err
+	}
+	targetCurrency, err = domain.NormalizeCurrency(targetCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if source.Currency == targetCurrency {
+		return nil, errors.New("source and target currency must differ")
+	}
+
+	stored, inverted, spreadBps, err := s.lookupRate(ctx, source.Currency, targetCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	midRate, rate := stored, stored.WithSpread(spreadBps)
+	var mid, target domain.Money
+	if inverted {
+		// Only the opposite pair is stored: divide by its rate, and show the inverse rates
+		// truncated to eight decimals
+		midRate = stored.Inverse()
+		rate = midRate.WithSpread(spreadBps)
+		if mid, err = stored.ConvertInverse(source, targetCurrency, 0); err == nil {
+			target, err = stored.ConvertInverse(source, targetCurrency, spreadBps)
+		}
+	} else {
+		if mid, err = midRate.Convert(source, targetCurrency); err == nil {
+			target, err = rate.Convert(source, targetCurrency)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !target.IsPositive() {
+		return nil, errors.New("amount is too small to convert")
+	}
+
+	id := utils.GenerateRandomString(16)
+	if id == "" {
+		return nil, errors.New("failed to generate quote id")
+	}
+
+	now := time.Now()
+	quote := &domain.FXQuote{
+		ID:             id,
+		UserID:         userID,
+		SourceCurrency: source.Currency,
+		TargetCurrency: targetCurrency,
+		SourceAmount:   source,
+		TargetAmount:   target,
+		MidRate:        midRate,
+		Rate:           rate,
+		SpreadBps:      spreadBps,
+		Spread:         mid.Sub(target),
+		ExpiresAt:      now.Add(s.quoteTTL),
+		CreatedAt:      now,
+	}
+	if err := s.repo.CreateQuote(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to store quote: %w", err)
+	}
+	return quote, nil
+}
+
+// lookupRate finds the mid rate of a pair. When only the opposite pair is stored it returns
+// that rate with inverted set.
+func (s *DefaultFXService) lookupRate(ctx context.Context, from, to string) (domain.Rate, bool, int, error) {
+	rate, err := s.repo.GetRate(ctx, from, to)
+	if err != nil {
+		return domain.Rate{}, false, 0, err
+	}
+	if rate != nil {
+		return rate.Rate, false, rate.SpreadBps, nil
+	}
+
+	inverse, err := s.repo.GetRate(ctx, to, from)
+	if err != nil {
+		return domain.Rate{}, false, 0, err
+	}
+	if inverse == nil {
+		return domain.Rate{}, false, 0, fmt.Errorf("%w: %s/%s", domain.ErrRateNotFound, from, to)
+	}
+	return inverse.Rate, true, inverse.SpreadBps, nil
+}
+
+func normalizeFXRate(rate *domain.FXRate) error {
+	if strings.TrimSpace(rate.BaseCurrency) == "" || strings.TrimSpace(rate.QuoteCurrency) == "" {
+		return fmt.Errorf("%w: base and quote currency are required", domain.ErrInvalidCurrency)
+	}
+	var err error
+	if rate.BaseCurrency, err = domain.NormalizeCurrency(rate.BaseCurrency); err != nil {
+		return err
+	}
+	if rate.QuoteCurrency, err = domain.NormalizeCurrency(rate.QuoteCurrency); err != nil {
+		return err
+	}
+	if rate.BaseCurrency == rate.QuoteCurrency {
+		return errors.New("base and quote currency must differ")
+	}
+	if rate.Rate.IsZero() {
+		return domain.ErrInvalidRate
+	}
+	if rate.SpreadBps < 0 || rate.SpreadBps >= 10_000 {
+		return errors.New("spread_bps must be between 0 and 9999")
+	}
+	return nil
+}
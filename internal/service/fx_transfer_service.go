@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// TransferWithQuote debits the source currency wallet of the sender and credits the target
+// currency wallet of the receiver at the quoted rate, atomically. A sender may use a quote
+// to convert between their own wallets, in which case the target wallet is created on demand.
+func (s *DefaultWalletService) TransferWithQuote(ctx context.Context, senderUserID, receiverUserID int64, quoteID string) (*domain.Transaction, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock the quote so it can only be used once
+	quote, err := s.fxRepo.GetQuoteForUpdate(ctx, txDb, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch quote: %w", err)
+	}
+	if quote == nil || quote.UserID != senderUserID {
+		return nil, domain.ErrQuoteNotFound
+	}
+	if quote.UsedAt != nil {
+		return nil, domain.ErrQuoteUsed
+	}
+	now := time.Now()
+	if now.After(quote.ExpiresAt) {
+		return nil, domain.ErrQuoteExpired
+	}
+	source, target := quote.SourceAmount, quote.TargetAmount
+
+	// 2. Resolve the wallets: the sender's, the receiver's and the FX position wallet of each
+	// currency, which buys the source amount and sells the target amount
+	senderWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, senderUserID, source.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sender wallet: %w", err)
+	}
+	if senderWallet == nil {
+		return nil, errors.New("sender wallet not found")
+	}
+	receiverWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, receiverUserID, target.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+	}
+	if receiverWallet == nil && receiverUserID != senderUserID {
+		return nil, s.missingReceiverWallet(ctx, receiverUserID, target.Currency)
+	}
+	sourcePosition, err := s.systemWallet(ctx, txDb, domain.SystemWalletFXPosition, source.Currency)
+	if err != nil {
+		return nil, err
+	}
+	targetPosition, err := s.systemWallet(ctx, txDb, domain.SystemWalletFXPosition, target.Currency)
+	if err != nil {
+		return nil, err
+	}
+	walletIDs := []int64{senderWallet.ID, sourcePosition.ID, targetPosition.ID}
+	if receiverWallet != nil {
+		walletIDs = append(walletIDs, receiverWallet.ID)
+	}
+
+	// 3. Lock every wallet in id order, the same way Transfer does
+	wallets, err := s.lockWallets(ctx, txDb, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	senderWallet = wallets[senderWallet.ID]
+	sourcePosition, targetPosition = wallets[sourcePosition.ID], wallets[targetPosition.ID]
+	if receiverWallet != nil {
+		receiverWallet = wallets[receiverWallet.ID]
+	} else {
+		// A concurrent first conversion may have created the wallet while we waited
+		if receiverWallet, err = s.wRepo.GetWalletForUpdate(ctx, txDb, receiverUserID, target.Currency); err != nil {
+			return nil, fmt.Errorf("failed to check/lock wallet: %w", err)
+		}
+		if receiverWallet == nil {
+			receiverWallet = &domain.Wallet{
+				UserID:    &receiverUserID,
+				Currency:  target.Currency,
+				Name:      domain.PrimaryPocketName,
+				IsPrimary: true,
+				Balance:   domain.NewMoney(0, target.Currency),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := s.wRepo.CreateWithTx(ctx, txDb, receiverWallet); err != nil {
+				return nil, fmt.Errorf("failed to create wallet: %w", err)
+			}
+		}
+	}
+
+	// 4. Check both wallets accept the movement and the balance covers the source amount
+	if err := senderWallet.CanDebit(); err != nil {
+		return nil, fmt.Errorf("sender %w", err)
+	}
+	if err := receiverWallet.CanCredit(); err != nil {
+		return nil, fmt.Errorf("receiver %w", err)
+	}
+	if senderWallet.Available().LessThan(source) {
+		return nil, domain.ErrInsufficientBalance
+	}
+	if err := s.checkLimits(ctx, txDb, senderWallet, source, source, 1); err != nil {
+		return nil, err
+	}
+
+	// 5. Move the balances: the source amount into the source position, the target amount out
+	// of the target position
+	for _, move := range []struct {
+		wallet *domain.Wallet
+		delta  domain.Money
+	}{
+		{senderWallet, source.Neg()},
+		{sourcePosition, source},
+		{targetPosition, target.Neg()},
+		{receiverWallet, target},
+	} {
+		move.wallet.Balance = move.wallet.Balance.Add(move.delta)
+		if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, move.wallet.ID, move.wallet.Balance); err != nil {
+			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+	}
+
+	// 6. Record the transfer with the rate and spread it was priced at
+	rate, spread, counterCurrency := quote.Rate, quote.Spread, target.Currency
+	transaction := &domain.Transaction{
+		SenderWalletID:   &senderWallet.ID,
+		ReceiverWalletID: &receiverWallet.ID,
+		Type:             domain.TransactionTypeTransfer,
+		Amount:           source,
+		CounterAmount:    &target,
+		CounterCurrency:  &counterCurrency,
+		FxRate:           &rate,
+		FxSpread:         &spread,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+	if err := s.fxRepo.MarkQuoteUsedWithTx(ctx, txDb, quote.ID, transaction.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to mark quote used: %w", err)
+	}
+
+	// 7. Record what the positions exchanged, so replaying transactions moves them as well: the
+	// target position paid out the target amount and the source position took the source amount
+	sourceCurrency := source.Currency
+	exchange := &domain.Transaction{
+		SenderWalletID:   &targetPosition.ID,
+		ReceiverWalletID: &sourcePosition.ID,
+		Type:             domain.TransactionTypeExchange,
+		Amount:           target,
+		CounterAmount:    &source,
+		CounterCurrency:  &sourceCurrency,
+		ExchangeForID:    &transaction.ID,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, exchange); err != nil {
+		return nil, fmt.Errorf("failed to create exchange record: %w", err)
+	}
+
+	// 8. Each currency balances on its own through its FX position wallet
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(senderWallet, domain.LedgerDebit, source),
+		walletPosting(sourcePosition, domain.LedgerCredit, source),
+		walletPosting(targetPosition, domain.LedgerDebit, target),
+		walletPosting(receiverWallet, domain.LedgerCredit, target),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
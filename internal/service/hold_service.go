@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+const (
+	// DefaultHoldTTL is how long a hold reserves funds when no expiry is requested
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	// MaxHoldTTL is the longest a hold may reserve funds
+	MaxHoldTTL = 30 * 24 * time.Hour
+
+	holdSweepBatchSize = 100
+)
+
+// Hold reserves amount in the payer wallet for a merchant. The funds stay in the balance but
+// no longer count as available until the hold is captured, voided or expires.
+func (s *DefaultWalletService) Hold(ctx context.Context, userID, merchantUserID int64, amount domain.Money, expiresIn time.Duration) (*domain.Hold, error) {
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if userID == merchantUserID {
+		return nil, errors.New("cannot place a hold for yourself")
+	}
+	if expiresIn <= 0 {
+		expiresIn = DefaultHoldTTL
+	}
+	if expiresIn > MaxHoldTTL {
+		return nil, fmt.Errorf("hold cannot last longer than %s", MaxHoldTTL)
+	}
+
+	// The merchant must be able to receive the currency when the hold is captured
+	merchantWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, merchantUserID, amount.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch merchant wallet: %w", err)
+	}
+	if merchantWallet == nil {
+		return nil, s.missingReceiverWallet(ctx, merchantUserID, amount.Currency)
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	wallet, err := s.wRepo.GetWalletForUpdate(ctx, txDb, userID, amount.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	if err := wallet.CanDebit(); err != nil {
+		return nil, err
+	}
+	if wallet.Available().LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+	// The hold counts against the limits from now on, its capture takes its place
+	if err := s.checkLimits(ctx, txDb, wallet, amount, amount, 1); err != nil {
+		return nil, err
+	}
+
+	if err := s.wRepo.UpdateHeldBalanceWithTx(ctx, txDb, wallet.ID, wallet.HeldBalance.Add(amount)); err != nil {
+		return nil, fmt.Errorf("failed to update held balance: %w", err)
+	}
+
+	now := time.Now()
+	hold := &domain.Hold{
+		WalletID:       wallet.ID,
+		MerchantUserID: merchantUserID,
+		Amount:         amount,
+		Status:         domain.HoldStatusActive,
+		ExpiresAt:      now.Add(expiresIn),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.hRepo.CreateWithTx(ctx, txDb, hold); err != nil {
+		return nil, fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return hold, nil
+}
+
+// Capture moves up to the held amount from the payer to the merchant. A hold is captured
+// once; whatever is not captured is released back to the payer.
+func (s *DefaultWalletService) Capture(ctx context.Context, merchantUserID, holdID int64, amount domain.Money) (*domain.Transaction, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock the hold so it can only be settled once
+	hold, err := s.activeHoldForUpdate(ctx, txDb, merchantUserID, holdID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !now.Before(hold.ExpiresAt) {
+		return nil, domain.ErrHoldExpired
+	}
+
+	if amount.IsZero() {
+		amount = hold.Amount
+	} else {
+		if amount.Currency == "" {
+			amount.Currency = hold.Currency
+		}
+		if amount, err = validateAmount(amount); err != nil {
+			return nil, err
+		}
+		if err := amount.SameCurrency(hold.Amount); err != nil {
+			return nil, err
+		}
+		if hold.Amount.LessThan(amount) {
+			return nil, domain.ErrCaptureExceedsHold
+		}
+	}
+
+	// 2. Lock the payer and merchant wallets
+	merchantWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, merchantUserID, hold.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch merchant wallet: %w", err)
+	}
+	if merchantWallet == nil {
+		return nil, s.missingReceiverWallet(ctx, merchantUserID, hold.Currency)
+	}
+	payer, merchant, err := s.lockWalletPair(ctx, txDb, hold.WalletID, merchantWallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMovement(payer, merchant); err != nil {
+		return nil, err
+	}
+
+	// 3. Release the whole hold and move the captured part
+	payer.HeldBalance = payer.HeldBalance.Sub(hold.Amount)
+	if err := s.wRepo.UpdateHeldBalanceWithTx(ctx, txDb, payer.ID, payer.HeldBalance); err != nil {
+		return nil, fmt.Errorf("failed to update held balance: %w", err)
+	}
+	payer.Balance = payer.Balance.Sub(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, payer.ID, payer.Balance); err != nil {
+		return nil, fmt.Errorf("failed to update payer balance: %w", err)
+	}
+	merchant.Balance = merchant.Balance.Add(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, merchant.ID, merchant.Balance); err != nil {
+		return nil, fmt.Errorf("failed to update merchant balance: %w", err)
+	}
+
+	// 4. Record the capture and settle the hold
+	transaction := &domain.Transaction{
+		SenderWalletID:   &payer.ID,
+		ReceiverWalletID: &merchant.ID,
+		Type:             domain.TransactionTypeCapture,
+		Amount:           amount,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+
+	hold.Status = domain.HoldStatusCaptured
+	hold.CapturedAmount = &amount
+	hold.TransactionID = &transaction.ID
+	hold.UpdatedAt = now
+	if err := s.hRepo.UpdateWithTx(ctx, txDb, hold); err != nil {
+		return nil, fmt.Errorf("failed to update hold: %w", err)
+	}
+
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(payer, domain.LedgerDebit, amount),
+		walletPosting(merchant, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// Void releases a hold without moving any money
+func (s *DefaultWalletService) Void(ctx context.Context, merchantUserID, holdID int64) (*domain.Hold, error) {
+	return s.releaseHold(ctx, holdID, domain.HoldStatusVoided, func(hold *domain.Hold) error {
+		if hold.MerchantUserID != merchantUserID {
+			return domain.ErrHoldNotFound
+		}
+		return nil
+	})
+}
+
+// ExpireHolds releases active holds whose expiry has passed and returns how many it released.
+// Holds settled concurrently are skipped.
+func (s *DefaultWalletService) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	expired, err := s.hRepo.ListExpired(ctx, now, holdSweepBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired holds: %w", err)
+	}
+
+	released := 0
+	for _, candidate := range expired {
+		_, err := s.releaseHold(ctx, candidate.ID, domain.HoldStatusExpired, func(hold *domain.Hold) error {
+			if now.Before(hold.ExpiresAt) {
+				return domain.ErrHoldNotActive
+			}
+			return nil
+		})
+		if errors.Is(err, domain.ErrHoldNotActive) {
+			continue
+		}
+		if err != nil {
+			return released, fmt.Errorf("failed to expire hold %d: %w", candidate.ID, err)
+		}
+		released++
+	}
+	return released, nil
+}
+
+// releaseHold returns the held amount to the available balance and moves the hold to status.
+// check runs on the locked hold before anything is written.
+func (s *DefaultWalletService) releaseHold(ctx context.Context, holdID int64, status domain.HoldStatus, check func(*domain.Hold) error) (*domain.Hold, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	hold, err := s.hRepo.GetByIDForUpdate(ctx, txDb, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+	if hold == nil {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err := check(hold); err != nil {
+		return nil, err
+	}
+	if hold.Status != domain.HoldStatusActive {
+		return nil, domain.ErrHoldNotActive
+	}
+
+	wallet, err := s.wRepo.GetByIDForUpdate(ctx, txDb, hold.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	if err := s.wRepo.UpdateHeldBalanceWithTx(ctx, txDb, wallet.ID, wallet.HeldBalance.Sub(hold.Amount)); err != nil {
+		return nil, fmt.Errorf("failed to update held balance: %w", err)
+	}
+
+	hold.Status = status
+	hold.UpdatedAt = time.Now()
+	if err := s.hRepo.UpdateWithTx(ctx, txDb, hold); err != nil {
+		return nil, fmt.Errorf("failed to update hold: %w", err)
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return hold, nil
+}
+
+// activeHoldForUpdate locks a hold of the merchant and makes sure it can still be settled
+func (s *DefaultWalletService) activeHoldForUpdate(ctx context.Context, txDb *goqu.TxDatabase, merchantUserID, holdID int64) (*domain.Hold, error) {
+	hold, err := s.hRepo.GetByIDForUpdate(ctx, txDb, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+	if hold == nil || hold.MerchantUserID != merchantUserID {
+		return nil, domain.ErrHoldNotFound
+	}
+	if hold.Status != domain.HoldStatusActive {
+		return nil, domain.ErrHoldNotActive
+	}
+	return hold, nil
+}
+
+// StartHoldSweeper expires stale holds every interval until ctx is cancelled
+func StartHoldSweeper(ctx context.Context, svc domain.TransactionService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			released, err := svc.ExpireHolds(ctx, now)
+			if err != nil {
+				utils.LogErrorf("hold sweeper: %v", err)
+			}
+			if released > 0 {
+				utils.LogInfof("hold sweeper released %d expired holds", released)
+			}
+		}
+	}
+}
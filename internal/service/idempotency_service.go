@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+)
+
+// DefaultIdempotencyKeyTTL is how long a key is remembered when no TTL is configured
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+// DefaultIdempotencyService stores the response to the first request with an idempotency key and
+// hands it back to every retry of that request until the key expires
+type DefaultIdempotencyService struct {
+	repo domain.IdempotencyKeyRepository
+	ttl  time.Duration
+}
+
+// Ensure interface compliance
+var _ domain.IdempotencyService = &DefaultIdempotencyService{}
+
+func NewIdempotencyService(repo domain.IdempotencyKeyRepository, ttl time.Duration) domain.IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyKeyTTL
+	}
+	return &DefaultIdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin claims key for the user. It returns the key to run the request under, or the completed
+// key whose response answers a retry. A key used with another fingerprint is rejected. A key
+// without a stored response stays in flight until it expires: its request may still be running
+// or may have moved money before it died, so running it again is never safe.
+func (s *DefaultIdempotencyService) Begin(ctx context.Context, userID int64, key, fingerprint string) (*domain.IdempotencyKey, error) {
+	// Two rounds: an expired key found on the first one is removed and claimed afresh
+	for round := 0; round < 2; round++ {
+		now := time.Now()
+		record := &domain.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      domain.IdempotencyStatusProcessing,
+			ExpiresAt:   now.Add(s.ttl),
+		}
+
+		// 1. Claim the key; the unique key lets one of two concurrent requests through
+		created, err := s.repo.Create(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if created {
+			return record, nil
+		}
+
+		// 2. Somebody holds it already
+		existing, err := s.repo.GetByUserIDAndKey(ctx, userID, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+		}
+		if existing == nil {
+			continue // Released in the meantime
+		}
+		if !now.Before(existing.ExpiresAt) {
+			if err := s.repo.Delete(ctx, existing.ID); err != nil {
+				return nil, fmt.Errorf("failed to remove expired idempotency key: %w", err)
+			}
+			continue
+		}
+		if existing.Fingerprint != fingerprint {
+			return nil, domain.ErrIdempotencyKeyReused
+		}
+		if existing.Status == domain.IdempotencyStatusCompleted {
+			return existing, nil
+		}
+		return nil, domain.ErrIdempotencyKeyInFlight
+	}
+	return nil, domain.ErrIdempotencyKeyInFlight
+}
+
+// Complete stores the response to the request run under key
+func (s *DefaultIdempotencyService) Complete(ctx context.Context, key *domain.IdempotencyKey, code int, body []byte) error {
+	if err := s.repo.Complete(ctx, key.ID, code, body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	key.Status = domain.IdempotencyStatusCompleted
+	key.ResponseCode = &code
+	key.ResponseBody = body
+	return nil
+}
+
+// PurgeExpired removes keys past their expiry
+func (s *DefaultIdempotencyService) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	return s.repo.DeleteExpired(ctx, now)
+}
+
+// StartIdempotencyKeySweeper purges expired idempotency keys every interval until ctx is
+// cancelled. Expired keys are also replaced when reused, so this only keeps the table small.
+func StartIdempotencyKeySweeper(ctx context.Context, svc domain.IdempotencyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			purged, err := svc.PurgeExpired(ctx, now)
+			if err != nil {
+				utils.LogErrorf("idempotency key sweeper: %v", err)
+			}
+			if purged > 0 {
+				utils.LogInfof("idempotency key sweeper removed %d expired keys", purged)
+			}
+		}
+	}
+}
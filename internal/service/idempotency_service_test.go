@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet-api/internal/domain"
+)
+
+// memoryKeyID is the unique key of idempotency_keys, (user_id, idempotency_key)
+type memoryKeyID struct {
+	userID int64
+	key    string
+}
+
+// memoryIdempotencyKeys is an in-memory IdempotencyKeyRepository. beforeGet, when set, runs
+// between a lost claim and the lookup of the key that won it.
+type memoryIdempotencyKeys struct {
+	keys      map[memoryKeyID]*domain.IdempotencyKey
+	nextID    int64
+	beforeGet func()
+	err       error
+}
+
+func newMemoryIdempotencyKeys() *memoryIdempotencyKeys {
+	return &memoryIdempotencyKeys{keys: make(map[memoryKeyID]*domain.IdempotencyKey)}
+}
+
+func (r *memoryIdempotencyKeys) Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	id := memoryKeyID{key.UserID, key.Key}
+	if _, taken := r.keys[id]; taken {
+		return false, nil
+	}
+	r.nextID++
+	key.ID = r.nextID
+	stored := *key
+	r.keys[id] = &stored
+	return true, nil
+}
+
+func (r *memoryIdempotencyKeys) GetByUserIDAndKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error) {
+	if r.beforeGet != nil {
+		r.beforeGet()
+		r.beforeGet = nil
+	}
+	stored, ok := r.keys[memoryKeyID{userID, key}]
+	if !ok {
+		return nil, nil
+	}
+	found := *stored
+	return &found, nil
+}
+
+func (r *memoryIdempotencyKeys) Complete(ctx context.Context, id int64, code int, body []byte) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, stored := range r.keys {
+		if stored.ID == id {
+			stored.Status = domain.IdempotencyStatusCompleted
+			stored.ResponseCode = &code
+			stored.ResponseBody = body
+		}
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) Delete(ctx context.Context, id int64) error {
+	for key, stored := range r.keys {
+		if stored.ID == id {
+			delete(r.keys, key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for key, stored := range r.keys {
+		if !now.Before(stored.ExpiresAt) {
+			delete(r.keys, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestIdempotencyBeginClaimsNewKey(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+
+	key, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if key.ID == 0 || key.Status != domain.IdempotencyStatusProcessing {
+		t.Errorf("Begin() = %+v, want a claimed processing key", key)
+	}
+	if remaining := time.Until(key.ExpiresAt); remaining <= 0 || remaining > time.Hour {
+		t.Errorf("ExpiresAt is %s away, want within the TTL", remaining)
+	}
+}
+
+func TestIdempotencyBeginKeepsProcessingKeyInFlight(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "fp"); err != nil {
+		t.Fatal(err)
+	}
+
+	// However long ago it started, a key without a response is never run again
+	repo.keys[memoryKeyID{1, "key-1"}].CreatedAt = time.Now().Add(-50 * time.Minute)
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "fp"); !errors.Is(err, domain.ErrIdempotencyKeyInFlight) {
+		t.Errorf("Begin() error = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+}
+
+func TestIdempotencyBeginReplaysCompletedKey(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	key, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Complete(context.Background(), key, 500, []byte(`{"success":false}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if replayed.Status != domain.IdempotencyStatusCompleted || replayed.ResponseCode == nil || *replayed.ResponseCode != 500 {
+		t.Errorf("Begin() = %+v, want the stored 500 response", replayed)
+	}
+	if string(replayed.ResponseBody) != `{"success":false}` {
+		t.Errorf("ResponseBody = %s, want the stored body", replayed.ResponseBody)
+	}
+}
+
+func TestIdempotencyBeginRejectsReusedKey(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	key, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another body is rejected whether the first request is running or done
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "other"); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("Begin() while processing error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if err := svc.Complete(context.Background(), key, 200, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "other"); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("Begin() when completed error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestIdempotencyBeginReclaimsExpiredKey(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	first, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.keys[memoryKeyID{1, "key-1"}].ExpiresAt = time.Now().Add(-time.Second)
+
+	// Once expired the key is free again, even for another body
+	second, err := svc.Begin(context.Background(), 1, "key-1", "other")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if second.ID == first.ID || second.Status != domain.IdempotencyStatusProcessing || second.Fingerprint != "other" {
+		t.Errorf("Begin() = %+v, want a fresh claim", second)
+	}
+}
+
+func TestIdempotencyBeginRetriesKeyRemovedMeanwhile(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "fp"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The sweeper removes the key between the lost claim and the lookup
+	repo.beforeGet = func() { delete(repo.keys, memoryKeyID{1, "key-1"}) }
+	key, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if key.Status != domain.IdempotencyStatusProcessing {
+		t.Errorf("Begin() = %+v, want a fresh claim", key)
+	}
+}
+
+func TestIdempotencyBeginScopesKeysPerUser(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	first, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another user claims the same key for another body, independently of the first
+	second, err := svc.Begin(context.Background(), 2, "key-1", "other")
+	if err != nil {
+		t.Fatalf("Begin() for another user error = %v", err)
+	}
+	if second.ID == first.ID || second.UserID != 2 || second.Status != domain.IdempotencyStatusProcessing {
+		t.Errorf("Begin() for another user = %+v, want a fresh claim", second)
+	}
+
+	if err := svc.Complete(context.Background(), second, 201, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "fp"); !errors.Is(err, domain.ErrIdempotencyKeyInFlight) {
+		t.Errorf("Begin() for the first user error = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+	replayed, err := svc.Begin(context.Background(), 2, "key-1", "other")
+	if err != nil || replayed.Status != domain.IdempotencyStatusCompleted {
+		t.Errorf("Begin() for the second user = %+v, %v; want its stored response", replayed, err)
+	}
+}
+
+func TestNewIdempotencyServiceDefaultTTL(t *testing.T) {
+	svc := NewIdempotencyService(newMemoryIdempotencyKeys(), 0).(*DefaultIdempotencyService)
+	if svc.ttl != DefaultIdempotencyKeyTTL {
+		t.Errorf("ttl = %s, want %s", svc.ttl, DefaultIdempotencyKeyTTL)
+	}
+}
+
+func TestIdempotencyBeginAndCompleteFail(t *testing.T) {
+	repo := newMemoryIdempotencyKeys()
+	svc := NewIdempotencyService(repo, time.Hour)
+	key, err := svc.Begin(context.Background(), 1, "key-1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.err = errors.New("connection lost")
+	if err := svc.Complete(context.Background(), key, 201, []byte(`{}`)); err == nil {
+		t.Error("Complete() returned no error")
+	}
+	if _, err := svc.Begin(context.Background(), 1, "key-2", "fp"); err == nil {
+		t.Error("Begin() returned no error")
+	}
+
+	// A response that could not be stored leaves the key in flight, not free to run again
+	repo.err = nil
+	if _, err := svc.Begin(context.Background(), 1, "key-1", "fp"); !errors.Is(err, domain.ErrIdempotencyKeyInFlight) {
+		t.Errorf("Begin() error = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+}
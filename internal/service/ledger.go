@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/utils"
+)
+
+// walletPosting builds a posting against a wallet. It must be called after wallet.Balance
+// has been updated so the balance-after snapshot matches what is written to wallets.balance.
+func walletPosting(wallet *domain.Wallet, direction domain.LedgerDirection, amount domain.Money) domain.LedgerEntry {
+	balanceAfter := wallet.Balance
+	return domain.LedgerEntry{
+		WalletID:     &wallet.ID,
+		Account:      domain.WalletLedgerAccount(wallet.ID),
+		Direction:    direction,
+		Amount:       amount,
+		BalanceAfter: &balanceAfter,
+	}
+}
+
+// externalPosting builds a posting against an account that is not backed by a wallet
+func externalPosting(account string, direction domain.LedgerDirection, amount domain.Money) domain.LedgerEntry {
+	return domain.LedgerEntry{
+		Account:   account,
+		Direction: direction,
+		Amount:    amount,
+	}
+}
+
+// postJournal validates and writes a balanced set of postings inside txDb
+func (s *DefaultWalletService) postJournal(ctx context.Context, txDb interface{}, transactionID *int64, entries ...domain.LedgerEntry) error {
+	return writeJournal(ctx, s.lRepo, txDb, transactionID, entries...)
+}
+
+// writeJournal stamps entries with a shared journal id and writes them through lRepo
+func writeJournal(ctx context.Context, lRepo domain.LedgerRepository, txDb interface{}, transactionID *int64, entries ...domain.LedgerEntry) error {
+	if err := domain.ValidateJournal(entries); err != nil {
+		return err
+	}
+
+	journalID := utils.GenerateRandomString(16)
+	if journalID == "" {
+		return fmt.Errorf("failed to generate journal id")
+	}
+	now := time.Now()
+	for i := range entries {
+		entries[i].JournalID = journalID
+		entries[i].TransactionID = transactionID
+		entries[i].Currency = entries[i].Amount.Currency
+		entries[i].CreatedAt = now
+	}
+
+	if err := lRepo.CreateWithTx(ctx, txDb, entries); err != nil {
+		return fmt.Errorf("failed to write ledger entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// DefaultLimitService configures the global and per-user transfer limits
+type DefaultLimitService struct {
+	repo domain.LimitRepository
+}
+
+// Ensure interface compliance
+var _ domain.LimitService = &DefaultLimitService{}
+
+func NewLimitService(repo domain.LimitRepository) domain.LimitService {
+	return &DefaultLimitService{repo: repo}
+}
+
+func (s *DefaultLimitService) List(ctx context.Context) ([]domain.TransferLimit, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *DefaultLimitService) Effective(ctx context.Context, userID int64, currency string) (*domain.TransferLimit, error) {
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	return effectiveLimits(ctx, s.repo, userID, currency)
+}
+
+func (s *DefaultLimitService) Set(ctx context.Context, limit *domain.TransferLimit) error {
+	currency, err := domain.NormalizeCurrency(limit.Currency)
+	if err != nil {
+		return err
+	}
+	limit.Currency = currency
+	for _, m := range []*domain.Money{limit.PerTransactionMax, limit.DailyAmountMax, limit.MonthlyAmountMax} {
+		if m == nil {
+			continue
+		}
+		m.Currency = currency
+		if m.IsNegative() {
+			return errors.New("limit amounts cannot be negative")
+		}
+	}
+	for _, n := range []*int{limit.DailyCountMax, limit.MonthlyCountMax} {
+		if n != nil && *n < 0 {
+			return errors.New("limit counts cannot be negative")
+		}
+	}
+	return s.repo.Upsert(ctx, limit)
+}
+
+func (s *DefaultLimitService) Delete(ctx context.Context, userID int64, currency string) error {
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, userID, currency)
+}
+
+// effectiveLimits merges the user override for currency onto the global limits
+func effectiveLimits(ctx context.Context, repo domain.LimitRepository, userID int64, currency string) (*domain.TransferLimit, error) {
+	global, err := repo.Get(ctx, 0, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch global limits: %w", err)
+	}
+	limits := domain.TransferLimit{Currency: currency}
+	if global != nil {
+		limits = *global
+	}
+	if userID != 0 {
+		override, err := repo.Get(ctx, userID, currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch user limits: %w", err)
+		}
+		limits = limits.Merge(override)
+	}
+	return &limits, nil
+}
+
+// checkLimits rejects count transfers worth total (largest being the biggest single one) when
+// they would breach the limits of the wallet owner. Usage is summed over every wallet the owner
+// has in the currency, and checks of the same owner serialize on the lock of their primary
+// wallet so concurrent transfers, even from different pockets, cannot both fit under the same
+// cap. Callers lock the primary wallet along with their other wallets to keep the id order;
+// locking it again here is then a no-op.
+func (s *DefaultWalletService) checkLimits(ctx context.Context, txDb *goqu.TxDatabase, wallet *domain.Wallet, largest, total domain.Money, count int) error {
+	if wallet.UserID == nil {
+		return nil // System wallets move money on behalf of users and are not limited
+	}
+	userID := *wallet.UserID
+	if _, err := s.wRepo.GetWalletForUpdate(ctx, txDb, userID, wallet.Currency); err != nil {
+		return fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	limits, err := effectiveLimits(ctx, s.limitRepo, userID, wallet.Currency)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	usage, err := s.tRepo.OutgoingUsageWithTx(ctx, txDb, userID, wallet.Currency, dayStart, monthStart)
+	if err != nil {
+		return fmt.Errorf("failed to compute transfer usage: %w", err)
+	}
+	usage.DailyAmount.Currency = wallet.Currency
+	usage.MonthlyAmount.Currency = wallet.Currency
+
+	return limits.Check(largest, total, count, usage)
+}
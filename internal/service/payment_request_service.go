@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+const (
+	// DefaultPaymentRequestTTL is how long a payment request can be accepted when no TTL is configured
+	DefaultPaymentRequestTTL = 72 * time.Hour
+
+	paymentRequestNoteMaxLen = 255
+)
+
+// DefaultPaymentRequestService lets users request money from each other. Accepting a request
+// pays it through the transaction service.
+type DefaultPaymentRequestService struct {
+	db        *sql.DB
+	repo      domain.PaymentRequestRepository
+	transfers domain.TransactionService
+	ttl       time.Duration
+}
+
+// Ensure interface compliance
+var _ domain.PaymentRequestService = &DefaultPaymentRequestService{}
+
+func NewPaymentRequestService(db *sql.DB, repo domain.PaymentRequestRepository, transfers domain.TransactionService, ttl time.Duration) domain.PaymentRequestService {
+	if ttl <= 0 {
+		ttl = DefaultPaymentRequestTTL
+	}
+	return &DefaultPaymentRequestService{db: db, repo: repo, transfers: transfers, ttl: ttl}
+}
+
+func (s *DefaultPaymentRequestService) Create(ctx context.Context, request *domain.PaymentRequest) error {
+	amount, err := validateAmount(request.Amount)
+	if err != nil {
+		return err
+	}
+	if request.RequesterUserID == request.PayerUserID {
+		return errors.New("cannot request money from yourself")
+	}
+	request.Note = strings.TrimSpace(request.Note)
+	if len(request.Note) > paymentRequestNoteMaxLen {
+		return fmt.Errorf("note cannot be longer than %d characters", paymentRequestNoteMaxLen)
+	}
+
+	now := time.Now()
+	request.Amount = amount
+	request.Status = domain.PaymentRequestStatusPending
+	request.ExpiresAt = now.Add(s.ttl)
+	request.CreatedAt = now
+	request.UpdatedAt = now
+	return s.repo.Create(ctx, request)
+}
+
+func (s *DefaultPaymentRequestService) Get(ctx context.Context, userID, id int64) (*domain.PaymentRequest, error) {
+	request, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || (request.PayerUserID != userID && request.RequesterUserID != userID) {
+		return nil, domain.ErrPaymentRequestNotFound
+	}
+	request.ApplyExpiry(time.Now())
+	return request, nil
+}
+
+func (s *DefaultPaymentRequestService) ListIncoming(ctx context.Context, payerUserID int64, status domain.PaymentRequestStatus) ([]domain.PaymentRequest, error) {
+	return s.repo.GetByPayerID(ctx, payerUserID, status, time.Now())
+}
+
+func (s *DefaultPaymentRequestService) ListOutgoing(ctx context.Context, requesterUserID int64, status domain.PaymentRequestStatus) ([]domain.PaymentRequest, error) {
+	return s.repo.GetByRequesterID(ctx, requesterUserID, status, time.Now())
+}
+
+// Accept claims the request and pays it with a transfer from the payer to the requester. The
+// claim, the transfer and the link to it commit together; if the transfer fails the request
+// stays pending so the payer can try again.
+func (s *DefaultPaymentRequestService) Accept(ctx context.Context, payerUserID, id int64) (*domain.PaymentRequest, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	request, err := s.respondWithTx(ctx, txDb, id, domain.PaymentRequestStatusAccepted, func(r *domain.PaymentRequest) bool {
+		return r.PayerUserID == payerUserID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transfers.TransferWithTx(ctx, txDb, request.PayerUserID, 0, request.RequesterUserID, request.Amount, domain.TransferMemo{Description: request.Note})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTransactionWithTx(ctx, txDb, request.ID, transaction.ID); err != nil {
+		return nil, fmt.Errorf("failed to link payment request to transaction: %w", err)
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	request.TransactionID = &transaction.ID
+	return request, nil
+}
+
+func (s *DefaultPaymentRequestService) Decline(ctx context.Context, payerUserID, id int64) (*domain.PaymentRequest, error) {
+	return s.respond(ctx, id, domain.PaymentRequestStatusDeclined, func(r *domain.PaymentRequest) bool {
+		return r.PayerUserID == payerUserID
+	})
+}
+
+func (s *DefaultPaymentRequestService) Cancel(ctx context.Context, requesterUserID, id int64) (*domain.PaymentRequest, error) {
+	return s.respond(ctx, id, domain.PaymentRequestStatusCancelled, func(r *domain.PaymentRequest) bool {
+		return r.RequesterUserID == requesterUserID
+	})
+}
+
+// respond moves a pending request to status on behalf of the side allowed by owns
+func (s *DefaultPaymentRequestService) respond(ctx context.Context, id int64, status domain.PaymentRequestStatus, owns func(*domain.PaymentRequest) bool) (*domain.PaymentRequest, error) {
+	return s.respondWithTx(ctx, nil, id, status, owns)
+}
+
+// respondWithTx is respond inside tx, or on its own when tx is nil
+func (s *DefaultPaymentRequestService) respondWithTx(ctx context.Context, tx interface{}, id int64, status domain.PaymentRequestStatus, owns func(*domain.PaymentRequest) bool) (*domain.PaymentRequest, error) {
+	request, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || !owns(request) {
+		return nil, domain.ErrPaymentRequestNotFound
+	}
+
+	now := time.Now()
+	request.ApplyExpiry(now)
+	switch request.Status {
+	case domain.PaymentRequestStatusPending:
+	case domain.PaymentRequestStatusExpired:
+		return nil, domain.ErrPaymentRequestExpired
+	default:
+		return nil, domain.ErrPaymentRequestClosed
+	}
+
+	moved, err := s.repo.TransitionWithTx(ctx, tx, request.ID, domain.PaymentRequestStatusPending, status, now)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		// Someone responded first, or it expired in between
+		return nil, domain.ErrPaymentRequestClosed
+	}
+
+	request.Status = status
+	request.RespondedAt = &now
+	request.UpdatedAt = now
+	return request, nil
+}
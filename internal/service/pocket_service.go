@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// CreatePocket opens a named wallet for a user. The first wallet of a currency becomes its
+// primary wallet, the one transfers from other users are paid into.
+func (s *DefaultWalletService) CreatePocket(ctx context.Context, userID int64, name, currency string) (*domain.Wallet, error) {
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	name, err = s.pocketName(ctx, userID, 0, name)
+	if err != nil {
+		return nil, err
+	}
+	primary, err := s.wRepo.GetByUserIDAndCurrency(ctx, userID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check wallet: %w", err)
+	}
+
+	now := time.Now()
+	wallet := &domain.Wallet{
+		UserID:    &userID,
+		Currency:  currency,
+		Name:      name,
+		IsPrimary: primary == nil,
+		Balance:   domain.NewMoney(0, currency),
+		Status:    domain.WalletStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.wRepo.Create(ctx, wallet); err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+	wallet.ApplyCurrency()
+	return wallet, nil
+}
+
+// RenamePocket changes the name of an open pocket
+func (s *DefaultWalletService) RenamePocket(ctx context.Context, userID, walletID int64, name string) (*domain.Wallet, error) {
+	wallet, err := s.ownWallet(ctx, userID, walletID, "")
+	if err != nil {
+		return nil, err
+	}
+	if wallet.ArchivedAt != nil {
+		return nil, domain.ErrWalletArchived
+	}
+	if wallet.Name, err = s.pocketName(ctx, userID, walletID, name); err != nil {
+		return nil, err
+	}
+	if err := s.wRepo.UpdatePocketWithTx(ctx, nil, wallet); err != nil {
+		return nil, fmt.Errorf("failed to rename wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+// ArchivePocket closes an empty pocket for good. The primary wallet of a currency stays open
+// so transfers from other users always have somewhere to go.
+func (s *DefaultWalletService) ArchivePocket(ctx context.Context, userID, walletID int64) (*domain.Wallet, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	wallet, err := s.wRepo.GetByIDForUpdate(ctx, txDb, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil || !ownsWallet(wallet, userID) {
+		return nil, domain.ErrWalletNotFound
+	}
+	switch {
+	case wallet.ArchivedAt != nil:
+		return nil, domain.ErrWalletArchived
+	case wallet.IsPrimary:
+		return nil, domain.ErrPrimaryPocket
+	case !wallet.Balance.IsZero() || !wallet.HeldBalance.IsZero():
+		return nil, domain.ErrWalletNotEmpty
+	}
+
+	now := time.Now()
+	wallet.ArchivedAt = &now
+	if err := s.wRepo.UpdatePocketWithTx(ctx, txDb, wallet); err != nil {
+		return nil, fmt.Errorf("failed to archive wallet: %w", err)
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return wallet, nil
+}
+
+// MoveBetweenPockets moves money between two wallets of the same user and currency. The money
+// does not leave the user, so neither transfer limits nor fees apply.
+func (s *DefaultWalletService) MoveBetweenPockets(ctx context.Context, userID, fromWalletID, toWalletID int64, amount domain.Money) (*domain.Transaction, error) {
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if fromWalletID == toWalletID {
+		return nil, errors.New("cannot move money to the same pocket")
+	}
+	if _, err := s.ownWallet(ctx, userID, fromWalletID, amount.Currency); err != nil {
+		return nil, err
+	}
+	if _, err := s.ownWallet(ctx, userID, toWalletID, amount.Currency); err != nil {
+		return nil, err
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock both pockets in id order
+	from, to, err := s.lockWalletPair(ctx, txDb, fromWalletID, toWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMovement(from, to); err != nil {
+		return nil, err
+	}
+	if from.Available().LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	// 2. Move the balances
+	from.Balance = from.Balance.Sub(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, from.ID, from.Balance); err != nil {
+		return nil, fmt.Errorf("failed to update sender balance: %w", err)
+	}
+	to.Balance = to.Balance.Add(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, to.ID, to.Balance); err != nil {
+		return nil, fmt.Errorf("failed to update receiver balance: %w", err)
+	}
+
+	// 3. Record and post the move
+	now := time.Now()
+	transaction := &domain.Transaction{
+		SenderWalletID:   &from.ID,
+		ReceiverWalletID: &to.ID,
+		Type:             domain.TransactionTypePocket,
+		Amount:           amount,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(from, domain.LedgerDebit, amount),
+		walletPosting(to, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// ownWallet fetches a wallet of userID without locking it. Wallets of other users are reported
+// as not found. A non-empty currency must match the wallet currency.
+func (s *DefaultWalletService) ownWallet(ctx context.Context, userID, walletID int64, currency string) (*domain.Wallet, error) {
+	wallet, err := s.wRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil || !ownsWallet(wallet, userID) {
+		return nil, domain.ErrWalletNotFound
+	}
+	if currency != "" && wallet.Currency != currency {
+		return nil, &domain.CurrencyMismatchError{SourceCurrency: currency, TargetCurrency: wallet.Currency}
+	}
+	return wallet, nil
+}
+
+// pocketName validates a pocket name, which must be unique among the open pockets of a user.
+// exceptID is the pocket being renamed.
+func (s *DefaultWalletService) pocketName(ctx context.Context, userID, exceptID int64, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("pocket name is required")
+	}
+	if len([]rune(name)) > domain.MaxPocketNameLength {
+		return "", fmt.Errorf("pocket name must be at most %d characters", domain.MaxPocketNameLength)
+	}
+
+	wallets, err := s.wRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+	for _, wallet := range wallets {
+		if wallet.ID != exceptID && wallet.ArchivedAt == nil && strings.EqualFold(wallet.Name, name) {
+			return "", domain.ErrPocketNameTaken
+		}
+	}
+	return name, nil
+}
+
+func ownsWallet(wallet *domain.Wallet, userID int64) bool {
+	return wallet.UserID != nil && *wallet.UserID == userID
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/qris"
+	"wallet-api/internal/utils"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+const (
+	// DefaultQRCodeTTL is how long a dynamic QR code can be paid when no TTL is configured
+	DefaultQRCodeTTL = 15 * time.Minute
+
+	// QRNetworkGUI identifies this wallet in the merchant account template of a payload
+	QRNetworkGUI = "ID.TLAB.WALLET"
+
+	qrMerchantAccountID   = "26"
+	qrCountryCode         = "ID"
+	qrDefaultCity         = "Jakarta"
+	qrDefaultCategoryCode = "0000" // Not categorised; person-to-person codes have no MCC
+	qrMerchantNameMaxLen  = 25
+	qrMerchantCityMaxLen  = 15
+	qrReferenceMaxLen     = 25
+)
+
+// DefaultQRPaymentService issues merchant-presented QR codes and pays scanned ones through the
+// transaction service
+type DefaultQRPaymentService struct {
+	db        *sql.DB
+	repo      domain.QRCodeRepository
+	wRepo     domain.WalletRepository
+	users     domain.UserRepository
+	transfers domain.TransactionService
+	ttl       time.Duration
+}
+
+// Ensure interface compliance
+var _ domain.QRPaymentService = &DefaultQRPaymentService{}
+
+func NewQRPaymentService(db *sql.DB, repo domain.QRCodeRepository, wRepo domain.WalletRepository, users domain.UserRepository, transfers domain.TransactionService, ttl time.Duration) domain.QRPaymentService {
+	if ttl <= 0 {
+		ttl = DefaultQRCodeTTL
+	}
+	return &DefaultQRPaymentService{db: db, repo: repo, wRepo: wRepo, users: users, transfers: transfers, ttl: ttl}
+}
+
+// Generate issues a QR code paying into one of the user's wallets, the primary wallet of the
+// currency when code.WalletID is zero. A code with an amount is dynamic and can be paid once.
+func (s *DefaultQRPaymentService) Generate(ctx context.Context, userID int64, code *domain.QRCode) error {
+	currency := code.Currency
+	if code.Amount != nil {
+		amount, err := validateAmount(*code.Amount)
+		if err != nil {
+			return err
+		}
+		code.Amount = &amount
+		currency = amount.Currency
+	}
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	// 1. Bind the code to a wallet of the user
+	var wallet *domain.Wallet
+	if code.WalletID != 0 {
+		wallet, err = s.wRepo.GetByID(ctx, code.WalletID)
+	} else {
+		wallet, err = s.wRepo.GetByUserIDAndCurrency(ctx, userID, currency)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil || !ownsWallet(wallet, userID) {
+		return domain.ErrWalletNotFound
+	}
+	if wallet.Currency != currency {
+		return &domain.CurrencyMismatchError{SourceCurrency: currency, TargetCurrency: wallet.Currency}
+	}
+	if err := wallet.CanCredit(); err != nil {
+		return err
+	}
+
+	// 2. Fill in what the payer will see
+	if strings.TrimSpace(code.MerchantName) == "" {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			code.MerchantName = user.Name
+		}
+	}
+	code.MerchantName = qrText(code.MerchantName, qrMerchantNameMaxLen)
+	if code.MerchantName == "" {
+		return errors.New("merchant name is required")
+	}
+	code.MerchantCity = qrText(code.MerchantCity, qrMerchantCityMaxLen)
+	if code.MerchantCity == "" {
+		code.MerchantCity = qrDefaultCity
+	}
+	if code.Reference != nil {
+		reference := qrText(*code.Reference, qrReferenceMaxLen)
+		code.Reference = &reference
+		if reference == "" {
+			code.Reference = nil
+		}
+	}
+
+	now := time.Now()
+	code.WalletID = wallet.ID
+	code.Currency = currency
+	code.Type = domain.QRCodeTypeStatic
+	code.Status = domain.QRCodeStatusActive
+	code.ExpiresAt = nil
+	if code.Amount != nil {
+		code.Type = domain.QRCodeTypeDynamic
+		expiresAt := now.Add(s.ttl)
+		code.ExpiresAt = &expiresAt
+	}
+	code.Token = utils.GenerateRandomString(16)
+	if code.Token == "" {
+		return errors.New("failed to generate QR code token")
+	}
+
+	// 3. Encode the payload; it only carries the token, the wallet stays server-side
+	payload := qris.Payload{
+		Dynamic: code.Type == domain.QRCodeTypeDynamic,
+		MerchantAccounts: []qris.MerchantAccount{{
+			ID:         qrMerchantAccountID,
+			GUI:        QRNetworkGUI,
+			MerchantID: code.Token,
+		}},
+		MerchantCategoryCode: qrDefaultCategoryCode,
+		Currency:             currency,
+		CountryCode:          qrCountryCode,
+		MerchantName:         code.MerchantName,
+		MerchantCity:         code.MerchantCity,
+	}
+	if code.Amount != nil {
+		payload.Amount = code.Amount.String()
+	}
+	if code.Reference != nil {
+		payload.BillNumber = *code.Reference
+	}
+	if code.Payload, err = qris.Encode(payload); err != nil {
+		return err
+	}
+
+	code.CreatedAt = now
+	code.UpdatedAt = now
+	return s.repo.Create(ctx, code)
+}
+
+// List returns the QR codes of every wallet of the user
+func (s *DefaultQRPaymentService) List(ctx context.Context, userID int64) ([]domain.QRCode, error) {
+	wallets, err := s.wRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wallets) == 0 {
+		return []domain.QRCode{}, nil
+	}
+	walletIDs := make([]int64, 0, len(wallets))
+	for _, wallet := range wallets {
+		walletIDs = append(walletIDs, wallet.ID)
+	}
+	return s.repo.GetByWalletIDs(ctx, walletIDs, time.Now())
+}
+
+// Pay decodes a scanned payload, checks its CRC and pays the wallet the code is bound to. The
+// payload is only trusted for the token: amount and currency come from the stored code. A dynamic
+// code is claimed in the same database transaction as the transfer, so it stays active if the
+// transfer fails.
+func (s *DefaultQRPaymentService) Pay(ctx context.Context, payerUserID int64, payload string, amount domain.Money) (*domain.Transaction, error) {
+	// 1. Decode the payload and find the code it was issued as
+	decoded, err := qris.Decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidQRCode, err)
+	}
+	account, ok := decoded.Account(QRNetworkGUI)
+	if !ok || account.MerchantID == "" {
+		return nil, fmt.Errorf("%w: not issued by this wallet", domain.ErrInvalidQRCode)
+	}
+	code, err := s.repo.GetByToken(ctx, account.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		return nil, domain.ErrQRCodeNotFound
+	}
+	if code.Payload != strings.TrimSpace(payload) {
+		return nil, fmt.Errorf("%w: payload does not match the issued code", domain.ErrInvalidQRCode)
+	}
+
+	// 2. Work out the amount
+	now := time.Now()
+	code.ApplyExpiry(now)
+	switch code.Status {
+	case domain.QRCodeStatusActive:
+	case domain.QRCodeStatusExpired:
+		return nil, domain.ErrQRCodeExpired
+	default:
+		return nil, domain.ErrQRCodePaid
+	}
+	if code.Type == domain.QRCodeTypeDynamic {
+		if !amount.IsZero() && amount.Cmp(*code.Amount) != 0 {
+			return nil, fmt.Errorf("amount must be %s %s for this QR code", code.Amount.String(), code.Currency)
+		}
+		amount = *code.Amount
+	} else {
+		if amount.IsZero() {
+			return nil, errors.New("amount is required for a static QR code")
+		}
+		amount.Currency = code.Currency
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 3. Claim a dynamic code so it cannot be paid twice
+	if code.Type == domain.QRCodeTypeDynamic {
+		claimed, err := s.repo.TransitionWithTx(ctx, txDb, code.ID, domain.QRCodeStatusActive, domain.QRCodeStatusPaid, now)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			return nil, domain.ErrQRCodePaid
+		}
+	}
+
+	// 4. Pay the bound wallet
+	memo := domain.TransferMemo{Description: "QR payment to " + code.MerchantName}
+	if code.Reference != nil {
+		memo.Description += ", bill " + *code.Reference
+	}
+	transaction, err := s.transfers.TransferToWalletWithTx(ctx, txDb, payerUserID, 0, code.WalletID, amount, memo)
+	if err != nil {
+		return nil, err
+	}
+	if code.Type == domain.QRCodeTypeDynamic {
+		if err := s.repo.SetTransactionWithTx(ctx, txDb, code.ID, transaction.ID); err != nil {
+			return nil, fmt.Errorf("failed to link QR code to transaction: %w", err)
+		}
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// qrText keeps the printable ASCII characters EMVCo allows and cuts the text to max
+func qrText(text string, max int) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(text) {
+		if r >= 0x20 && r <= 0x7E {
+			b.WriteRune(r)
+		}
+	}
+	return truncate(strings.TrimSpace(b.String()), max)
+}
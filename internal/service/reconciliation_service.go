@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// AdjustmentReference marks the transactions written by reconciliation
+const AdjustmentReference = "reconciliation"
+
+// DefaultReconciliationService replays transaction history to find wallets whose cached
+// balance was changed without a matching transaction
+type DefaultReconciliationService struct {
+	db    *sql.DB
+	wRepo domain.WalletRepository
+	tRepo domain.TransactionRepository
+	lRepo domain.LedgerRepository
+}
+
+// Ensure interface compliance
+var _ domain.ReconciliationService = &DefaultReconciliationService{}
+
+func NewReconciliationService(db *sql.DB, wRepo domain.WalletRepository, tRepo domain.TransactionRepository, lRepo domain.LedgerRepository) domain.ReconciliationService {
+	return &DefaultReconciliationService{
+		db:    db,
+		wRepo: wRepo,
+		tRepo: tRepo,
+		lRepo: lRepo,
+	}
+}
+
+// Report compares every wallet with its replayed transactions and ledger postings. It reads
+// without locking, so a wallet that moves while the report runs can show a transient drift;
+// Fix re-checks under lock before writing anything.
+func (s *DefaultReconciliationService) Report(ctx context.Context) ([]domain.BalanceDrift, error) {
+	wallets, err := s.wRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	replayed, err := s.tRepo.ReplayBalances(ctx, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transactions: %w", err)
+	}
+	posted, err := s.lRepo.GetWalletBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum ledger postings: %w", err)
+	}
+
+	drifts := make([]domain.BalanceDrift, 0, len(wallets))
+	for i := range wallets {
+		drifts = append(drifts, newBalanceDrift(&wallets[i], replayed[wallets[i].ID], posted[wallets[i].ID]))
+	}
+	return drifts, nil
+}
+
+// Fix locks a wallet, measures its drift again and writes an adjustment transaction so the
+// history adds up to wallets.balance. The stored balance is what customers have seen, so it is
+// kept as is; the difference is moved from the suspense system wallet for review. The wallet
+// ledger is only posted for the part it is actually missing.
+func (s *DefaultReconciliationService) Fix(ctx context.Context, walletID int64) (*domain.BalanceDrift, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock the wallet and the suspense wallet of its currency so no transfer moves them while
+	// the drift is measured
+	wallet, err := s.wRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	suspense, err := systemWallet(ctx, s.wRepo, txDb, domain.SystemWalletSuspense, wallet.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if suspense.ID == wallet.ID {
+		return nil, errors.New("the suspense wallet cannot be adjusted against itself")
+	}
+	wallets, err := lockWallets(ctx, s.wRepo, txDb, wallet.ID, suspense.ID)
+	if err != nil {
+		return nil, err
+	}
+	wallet, suspense = wallets[wallet.ID], wallets[suspense.ID]
+
+	replayed, err := s.tRepo.ReplayBalances(ctx, txDb, []int64{wallet.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transactions: %w", err)
+	}
+	posted, err := s.lRepo.GetWalletBalance(ctx, wallet.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum ledger postings: %w", err)
+	}
+
+	drift := newBalanceDrift(wallet, replayed[wallet.ID], posted)
+	if !drift.HasDrift() {
+		return &drift, nil
+	}
+
+	// 2. Record the missing movement as an adjustment from the suspense wallet; the balance of
+	// the wallet itself does not change
+	var transactionID *int64
+	if transactionDrift := drift.TransactionDrift(); !transactionDrift.IsZero() {
+		reference := AdjustmentReference
+		now := time.Now()
+		adjustment := &domain.Transaction{
+			Type:              domain.TransactionTypeAdjustment,
+			Amount:            absMoney(transactionDrift),
+			Status:            domain.TransactionStatusSuccess,
+			ExternalReference: &reference,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		suspenseSide, accountSide := domain.LedgerDebit, domain.LedgerCredit
+		if transactionDrift.IsPositive() {
+			adjustment.SenderWalletID, adjustment.ReceiverWalletID = &suspense.ID, &wallet.ID
+		} else {
+			adjustment.SenderWalletID, adjustment.ReceiverWalletID = &wallet.ID, &suspense.ID
+			suspenseSide, accountSide = domain.LedgerCredit, domain.LedgerDebit
+		}
+		if err := s.tRepo.CreateWithTx(ctx, txDb, adjustment); err != nil {
+			return nil, fmt.Errorf("failed to create adjustment: %w", err)
+		}
+		transactionID = &adjustment.ID
+		drift.AdjustmentID = &adjustment.ID
+
+		suspense.Balance = suspense.Balance.Sub(transactionDrift)
+		if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, suspense.ID, suspense.Balance); err != nil {
+			return nil, fmt.Errorf("failed to update suspense balance: %w", err)
+		}
+		if err := writeJournal(ctx, s.lRepo, txDb, transactionID,
+			walletPosting(suspense, suspenseSide, adjustment.Amount),
+			externalPosting(domain.LedgerAccountSuspense, accountSide, adjustment.Amount),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Post whatever the ledger is missing against suspense
+	if ledgerDrift := drift.LedgerDrift(); !ledgerDrift.IsZero() {
+		walletSide, suspenseSide := domain.LedgerCredit, domain.LedgerDebit
+		if ledgerDrift.IsNegative() {
+			walletSide, suspenseSide = domain.LedgerDebit, domain.LedgerCredit
+		}
+		amount := absMoney(ledgerDrift)
+		if err := writeJournal(ctx, s.lRepo, txDb, transactionID,
+			walletPosting(wallet, walletSide, amount),
+			externalPosting(domain.LedgerAccountSuspense, suspenseSide, amount),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &drift, nil
+}
+
+// newBalanceDrift puts the three balances of a wallet side by side in the wallet currency
+func newBalanceDrift(wallet *domain.Wallet, replayed, posted domain.Money) domain.BalanceDrift {
+	return domain.BalanceDrift{
+		WalletID:        wallet.ID,
+		UserID:          wallet.UserID,
+		SystemCode:      wallet.SystemCode,
+		Currency:        wallet.Currency,
+		StoredBalance:   wallet.Balance,
+		ReplayedBalance: domain.NewMoney(replayed.MinorUnits, wallet.Currency),
+		LedgerBalance:   domain.NewMoney(posted.MinorUnits, wallet.Currency),
+	}
+}
+
+func absMoney(m domain.Money) domain.Money {
+	if m.IsNegative() {
+		return m.Neg()
+	}
+	return m
+}
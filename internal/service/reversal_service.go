@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// Reverse refunds a completed transfer by moving amount from the original receiver back to
+// the original sender. A zero amount reverses whatever has not been refunded yet; partial
+// refunds are allowed until the original amount is used up.
+func (s *DefaultWalletService) Reverse(ctx context.Context, transactionID int64, amount domain.Money, reason string) (*domain.Transaction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock the original so concurrent refunds of the same transfer run one at a time
+	original, err := s.tRepo.GetByIDForUpdate(ctx, txDb, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	if original == nil {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if original.Type != domain.TransactionTypeTransfer ||
+		original.Status != domain.TransactionStatusSuccess ||
+		original.SenderWalletID == nil || original.ReceiverWalletID == nil ||
+		original.CounterAmount != nil {
+		return nil, domain.ErrNotReversible
+	}
+
+	// 2. Work out how much is left to refund
+	reversed, err := s.tRepo.SumReversalsWithTx(ctx, txDb, original.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum previous reversals: %w", err)
+	}
+	reversed.Currency = original.Amount.Currency
+	remaining := original.Amount.Sub(reversed)
+	if !remaining.IsPositive() {
+		return nil, domain.ErrAlreadyReversed
+	}
+
+	if amount.IsZero() {
+		amount = remaining
+	} else {
+		if amount.Currency == "" {
+			amount.Currency = original.Amount.Currency
+		}
+		if amount, err = validateAmount(amount); err != nil {
+			return nil, err
+		}
+		if err := amount.SameCurrency(original.Amount); err != nil {
+			return nil, err
+		}
+		if remaining.LessThan(amount) {
+			return nil, domain.ErrReversalExceeds
+		}
+	}
+
+	// 3. Lock both wallets; the refund flows from the original receiver to the original sender
+	payer, payee, err := s.lockWalletPair(ctx, txDb, *original.ReceiverWalletID, *original.SenderWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if payer.Available().LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	newPayerBalance := payer.Balance.Sub(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, payer.ID, newPayerBalance); err != nil {
+		return nil, fmt.Errorf("failed to update receiver balance: %w", err)
+	}
+	payer.Balance = newPayerBalance
+
+	newPayeeBalance := payee.Balance.Add(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, payee.ID, newPayeeBalance); err != nil {
+		return nil, fmt.Errorf("failed to update sender balance: %w", err)
+	}
+	payee.Balance = newPayeeBalance
+
+	// 4. Record the refund, linked to the original transfer
+	now := time.Now()
+	refund := &domain.Transaction{
+		SenderWalletID:   &payer.ID,
+		ReceiverWalletID: &payee.ID,
+		Type:             domain.TransactionTypeRefund,
+		Amount:           amount,
+		ReversalOfID:     &original.ID,
+		ReversalReason:   &reason,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, refund); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+
+	if err := s.postJournal(ctx, txDb, &refund.ID,
+		walletPosting(payer, domain.LedgerDebit, amount),
+		walletPosting(payee, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return refund, nil
+}
+
+// lockWalletPair locks two wallets through lockWallets and returns them in the order they
+// were asked for
+func (s *DefaultWalletService) lockWalletPair(ctx context.Context, txDb *goqu.TxDatabase, firstID, secondID int64) (*domain.Wallet, *domain.Wallet, error) {
+	wallets, err := s.lockWallets(ctx, txDb, firstID, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wallets[firstID], wallets[secondID], nil
+}
+
+// lockWallets locks every wallet in ascending id order so that operations touching the same
+// wallets in a different order cannot deadlock. Duplicate ids are locked once.
+func (s *DefaultWalletService) lockWallets(ctx context.Context, txDb *goqu.TxDatabase, ids ...int64) (map[int64]*domain.Wallet, error) {
+	return lockWallets(ctx, s.wRepo, txDb, ids...)
+}
+
+// lockWallets locks wallets through wRepo, see DefaultWalletService.lockWallets
+func lockWallets(ctx context.Context, wRepo domain.WalletRepository, txDb *goqu.TxDatabase, ids ...int64) (map[int64]*domain.Wallet, error) {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	wallets := make(map[int64]*domain.Wallet, len(sorted))
+	for _, id := range sorted {
+		if _, locked := wallets[id]; locked {
+			continue
+		}
+		wallet, err := wRepo.GetByIDForUpdate(ctx, txDb, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+		}
+		if wallet == nil {
+			return nil, domain.ErrWalletNotFound
+		}
+		wallets[id] = wallet
+	}
+	return wallets, nil
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+const (
+	// ScheduleMaxAttempts is how many times an occurrence is tried while the balance is short
+	ScheduleMaxAttempts = 3
+	// ScheduleRetryDelay is the wait between attempts of the same occurrence
+	ScheduleRetryDelay = time.Hour
+	// SchedulePauseAfterFailures pauses a schedule once this many occurrences failed in a row
+	SchedulePauseAfterFailures = 3
+
+	scheduleLease         = 5 * time.Minute
+	scheduleBatchSize     = 100
+	scheduleExecutionsMax = 20
+	scheduleErrorMaxLen   = 255
+)
+
+// DefaultScheduleService stores scheduled transfers and runs them through the transaction service
+type DefaultScheduleService struct {
+	db        *sql.DB
+	repo      domain.ScheduleRepository
+	transfers domain.TransactionService
+}
+
+// Ensure interface compliance
+var _ domain.ScheduleService = &DefaultScheduleService{}
+
+func NewScheduleService(db *sql.DB, repo domain.ScheduleRepository, transfers domain.TransactionService) domain.ScheduleService {
+	return &DefaultScheduleService{db: db, repo: repo, transfers: transfers}
+}
+
+func (s *DefaultScheduleService) Create(ctx context.Context, schedule *domain.ScheduledTransfer) error {
+	now := time.Now()
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	if err := validateSchedule(schedule, now); err != nil {
+		return err
+	}
+
+	schedule.NextRunAt = schedule.StartAt
+	schedule.Status = domain.ScheduleStatusActive
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	return s.repo.Create(ctx, schedule)
+}
+
+func (s *DefaultScheduleService) List(ctx context.Context, userID int64) ([]domain.ScheduledTransfer, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// Get returns a schedule of the user with its latest executions
+func (s *DefaultScheduleService) Get(ctx context.Context, userID, id int64) (*domain.ScheduledTransfer, []domain.ScheduleExecution, error) {
+	schedule, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	executions, err := s.repo.GetExecutions(ctx, schedule.ID, scheduleExecutionsMax)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, executions, nil
+}
+
+// Update changes the amount, timing or status of a schedule. Changing the timing restarts
+// the schedule from the new StartAt; resuming a paused schedule clears its failure count and
+// skips the occurrences missed while it was paused.
+func (s *DefaultScheduleService) Update(ctx context.Context, userID int64, changes *domain.ScheduledTransfer) error {
+	schedule, err := s.owned(ctx, userID, changes.ID)
+	if err != nil {
+		return err
+	}
+	if schedule.Status == domain.ScheduleStatusCompleted || schedule.Status == domain.ScheduleStatusCancelled {
+		return domain.ErrScheduleClosed
+	}
+	if changes.Status != domain.ScheduleStatusActive && changes.Status != domain.ScheduleStatusPaused {
+		return errors.New("status must be active or paused")
+	}
+
+	now := time.Now()
+	retimed := !changes.StartAt.Equal(schedule.StartAt) || changes.Frequency != schedule.Frequency
+	changes.ReceiverUserID = schedule.ReceiverUserID
+	changes.UserID = schedule.UserID
+	if retimed {
+		if err := validateSchedule(changes, now); err != nil {
+			return err
+		}
+		schedule.StartAt = changes.StartAt
+		schedule.Frequency = changes.Frequency
+		schedule.Occurrences = 0
+		schedule.NextRunAt = changes.StartAt
+		schedule.Attempts = 0
+	} else {
+		changes.StartAt = schedule.StartAt
+		if err := validateSchedule(changes, time.Time{}); err != nil {
+			return err
+		}
+	}
+	schedule.Amount = changes.Amount
+	schedule.EndAt = changes.EndAt
+
+	if schedule.Status == domain.ScheduleStatusPaused && changes.Status == domain.ScheduleStatusActive {
+		schedule.ConsecutiveFailures = 0
+		schedule.Attempts = 0
+		if !retimed {
+			schedule.SkipMissed(now)
+		}
+	}
+	schedule.Status = changes.Status
+	if schedule.Status == domain.ScheduleStatusActive && schedule.Finished() {
+		schedule.Status = domain.ScheduleStatusCompleted
+	}
+
+	schedule.UpdatedAt = now
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return err
+	}
+	*changes = *schedule
+	return nil
+}
+
+// Cancel stops a schedule for good; its execution history is kept
+func (s *DefaultScheduleService) Cancel(ctx context.Context, userID, id int64) error {
+	schedule, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if schedule.Status == domain.ScheduleStatusCompleted || schedule.Status == domain.ScheduleStatusCancelled {
+		return domain.ErrScheduleClosed
+	}
+	schedule.Status = domain.ScheduleStatusCancelled
+	schedule.UpdatedAt = time.Now()
+	return s.repo.Update(ctx, schedule)
+}
+
+// RunDue runs every schedule that is due at now. Each schedule is claimed first so that
+// several API instances can run the worker side by side. A schedule that fails to run is
+// logged and left to its lease; the rest of the batch still runs.
+func (s *DefaultScheduleService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDue(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due schedules: %w", err)
+	}
+
+	processed := 0
+	leaseUntil := now.Add(scheduleLease).Truncate(time.Second)
+	for i := range due {
+		schedule := &due[i]
+		claimed, err := s.repo.Claim(ctx, schedule.ID, schedule.NextRunAt, leaseUntil)
+		if err != nil {
+			utils.LogErrorf("failed to claim schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		ran, err := s.run(ctx, schedule.ID, leaseUntil, now)
+		if err != nil {
+			utils.LogErrorf("failed to run schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		if ran {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// run executes the current occurrence of a claimed schedule and records the outcome. The
+// schedule is locked and read again first, so a cancel or change made since the claim is
+// respected rather than overwritten. A short balance is retried a bounded number of times;
+// any other error fails the occurrence. A successful transfer commits together with the
+// advanced schedule, and its client reference names the occurrence, so an occurrence can
+// never be paid twice.
+func (s *DefaultScheduleService) run(ctx context.Context, id int64, leaseUntil, now time.Time) (bool, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	schedule, err := s.repo.GetClaimedForUpdate(ctx, txDb, id, leaseUntil)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock schedule: %w", err)
+	}
+	if schedule == nil {
+		// Cancelled, paused or retimed since the claim
+		return false, nil
+	}
+
+	occurrenceAt := schedule.OccurrenceAt(schedule.Occurrences)
+	attempt := schedule.Attempts + 1
+	execution := &domain.ScheduleExecution{
+		ScheduleID:   schedule.ID,
+		OccurrenceAt: occurrenceAt,
+		Attempt:      attempt,
+		ExecutedAt:   now,
+	}
+
+	memo := domain.TransferMemo{ClientReference: scheduleReference(schedule.ID, occurrenceAt)}
+	transaction, transferErr := s.transfers.TransferWithTx(ctx, txDb, schedule.UserID, 0, schedule.ReceiverUserID, schedule.Amount, memo)
+	var tx interface{} = txDb
+	if transferErr == nil {
+		execution.Status = domain.TransactionStatusSuccess
+		execution.TransactionID = &transaction.ID
+		schedule.Attempts = 0
+		schedule.ConsecutiveFailures = 0
+		schedule.LastError = nil
+		schedule.Advance(now)
+	} else {
+		// Nothing of a failed transfer is kept; the failure is recorded on its own and only
+		// while the schedule is still claimed
+		if err := txDb.Rollback(); err != nil {
+			return false, fmt.Errorf("failed to roll back transfer: %w", err)
+		}
+		tx = nil
+
+		message := truncate(transferErr.Error(), scheduleErrorMaxLen)
+		execution.Status = domain.TransactionStatusFailed
+		execution.Error = &message
+		schedule.LastError = &message
+
+		if errors.Is(transferErr, domain.ErrInsufficientBalance) && attempt < ScheduleMaxAttempts {
+			schedule.Attempts = attempt
+			schedule.NextRunAt = now.Add(ScheduleRetryDelay).Truncate(time.Second)
+		} else {
+			schedule.Attempts = 0
+			schedule.ConsecutiveFailures++
+			schedule.Advance(now)
+			if schedule.ConsecutiveFailures >= SchedulePauseAfterFailures && schedule.Status == domain.ScheduleStatusActive {
+				schedule.Status = domain.ScheduleStatusPaused
+				utils.LogInfof("scheduled transfer %d paused after %d failed occurrences", schedule.ID, schedule.ConsecutiveFailures)
+			}
+		}
+	}
+
+	schedule.UpdatedAt = now
+	recorded, err := s.repo.RecordRunWithTx(ctx, tx, schedule, leaseUntil)
+	if err != nil {
+		return false, fmt.Errorf("failed to update schedule: %w", err)
+	}
+	if !recorded {
+		if tx != nil {
+			return false, errors.New("schedule changed while it was locked")
+		}
+		// The user changed the schedule while the failed transfer ran
+		return false, nil
+	}
+	if err := s.repo.CreateExecutionWithTx(ctx, tx, execution); err != nil {
+		return false, fmt.Errorf("failed to record execution: %w", err)
+	}
+	if tx == nil {
+		return true, nil
+	}
+	if err := txDb.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// scheduleReference is the client reference of the transfer paying one occurrence of a schedule
+func scheduleReference(scheduleID int64, occurrenceAt time.Time) string {
+	return fmt.Sprintf("schedule:%d:%s", scheduleID, occurrenceAt.UTC().Format("20060102T150405Z"))
+}
+
+// owned fetches a schedule and hides schedules of other users
+func (s *DefaultScheduleService) owned(ctx context.Context, userID, id int64) (*domain.ScheduledTransfer, error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.UserID != userID {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// validateSchedule checks a schedule before it is stored. A zero now skips the start check.
+func validateSchedule(schedule *domain.ScheduledTransfer, now time.Time) error {
+	amount, err := validateAmount(schedule.Amount)
+	if err != nil {
+		return err
+	}
+	schedule.Amount = amount
+	if schedule.UserID == schedule.ReceiverUserID {
+		return errors.New("cannot schedule a transfer to self")
+	}
+	switch schedule.Frequency {
+	case domain.ScheduleFrequencyOnce, domain.ScheduleFrequencyDaily, domain.ScheduleFrequencyWeekly, domain.ScheduleFrequencyMonthly:
+	default:
+		return errors.New("frequency must be once, daily, weekly or monthly")
+	}
+
+	schedule.StartAt = schedule.StartAt.Truncate(time.Second)
+	if !now.IsZero() && schedule.StartAt.Before(now.Add(-time.Minute)) {
+		return errors.New("start_at must not be in the past")
+	}
+	if schedule.EndAt != nil {
+		endAt := schedule.EndAt.Truncate(time.Second)
+		if endAt.Before(schedule.StartAt) {
+			return errors.New("end_at must be after start_at")
+		}
+		schedule.EndAt = &endAt
+	}
+	return nil
+}
+
+// truncate cuts s to at most max characters, never inside a UTF-8 sequence
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+// StartScheduleWorker runs due scheduled transfers every interval until ctx is cancelled
+func StartScheduleWorker(ctx context.Context, svc domain.ScheduleService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			processed, err := svc.RunDue(ctx, now)
+			if err != nil {
+				utils.LogErrorf("schedule worker: %v", err)
+			}
+			if processed > 0 {
+				utils.LogInfof("schedule worker ran %d scheduled transfers", processed)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/pdf"
+	"wallet-api/internal/pkg/utils"
+)
+
+const statementTimeLayout = "2006-01-02 15:04:05"
+
+// DefaultStatementService builds monthly statements from the transactions that moved a wallet.
+// Like reconciliation it replays rows that count towards the balance today, so a withdrawal that
+// failed later drops out of the month it was requested in on both sides.
+type DefaultStatementService struct {
+	wRepo domain.WalletRepository
+	tRepo domain.TransactionRepository
+	users domain.UserRepository
+}
+
+// Ensure interface compliance
+var _ domain.StatementService = &DefaultStatementService{}
+
+func NewStatementService(wRepo domain.WalletRepository, tRepo domain.TransactionRepository, users domain.UserRepository) domain.StatementService {
+	return &DefaultStatementService{
+		wRepo: wRepo,
+		tRepo: tRepo,
+		users: users,
+	}
+}
+
+// Generate builds the statement of one of the user's wallets for the month containing month
+func (s *DefaultStatementService) Generate(ctx context.Context, userID, walletID int64, currency string, month time.Time) (*domain.Statement, error) {
+	var wallet *domain.Wallet
+	var err error
+	if walletID != 0 {
+		wallet, err = s.wRepo.GetByID(ctx, walletID)
+	} else {
+		if currency, err = domain.NormalizeCurrency(currency); err != nil {
+			return nil, err
+		}
+		wallet, err = s.wRepo.GetByUserIDAndCurrency(ctx, userID, currency)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil || !ownsWallet(wallet, userID) {
+		return nil, domain.ErrWalletNotFound
+	}
+	return s.build(ctx, wallet, month)
+}
+
+// GenerateForWallet builds the statement of any wallet, system wallets included
+func (s *DefaultStatementService) GenerateForWallet(ctx context.Context, walletID int64, month time.Time) (*domain.Statement, error) {
+	wallet, err := s.wRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	return s.build(ctx, wallet, month)
+}
+
+func (s *DefaultStatementService) build(ctx context.Context, wallet *domain.Wallet, month time.Time) (*domain.Statement, error) {
+	now := time.Now()
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0)
+	if start.After(now) {
+		return nil, domain.ErrStatementPeriod
+	}
+
+	// 1. Opening balance from everything before the month
+	opening, err := s.tRepo.ReplayBalancesBefore(ctx, []int64{wallet.ID}, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transactions: %w", err)
+	}
+	statement := &domain.Statement{
+		WalletID:       wallet.ID,
+		WalletName:     wallet.Name,
+		Currency:       wallet.Currency,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		OpeningBalance: domain.NewMoney(opening[wallet.ID].MinorUnits, wallet.Currency),
+		TotalIn:        domain.NewMoney(0, wallet.Currency),
+		TotalOut:       domain.NewMoney(0, wallet.Currency),
+		Lines:          []domain.StatementLine{},
+		GeneratedAt:    now,
+	}
+	switch {
+	case wallet.UserID != nil:
+		user, err := s.users.GetByID(ctx, *wallet.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account holder: %w", err)
+		}
+		if user != nil {
+			statement.AccountHolder = user.Name
+		}
+	case wallet.SystemCode != nil:
+		statement.AccountHolder = "System: " + string(*wallet.SystemCode)
+	}
+
+	// 2. Every movement of the month with the running balance
+	transactions, err := s.tRepo.GetMovementsBetween(ctx, wallet.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	balance := statement.OpeningBalance
+	for i := range transactions {
+		transaction := &transactions[i]
+		line := domain.StatementLine{
+			TransactionID: transaction.ID,
+			Date:          transaction.CreatedAt,
+			Type:          transaction.Type,
+			Direction:     transaction.DirectionFor(wallet.ID),
+			Amount:        transaction.Amount,
+		}
+		if line.Direction == domain.TransactionDirectionIn {
+			if transaction.CounterAmount != nil {
+				line.Amount = *transaction.CounterAmount
+			}
+			balance = balance.Add(line.Amount)
+			statement.TotalIn = statement.TotalIn.Add(line.Amount)
+		} else {
+			balance = balance.Sub(line.Amount)
+			statement.TotalOut = statement.TotalOut.Add(line.Amount)
+		}
+		line.Balance = balance
+		line.Description = describeStatementLine(transaction, line.Direction)
+		if transaction.Description != nil {
+			line.Description += ": " + *transaction.Description
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalance = balance
+	return statement, nil
+}
+
+// WriteCSV renders a statement as one row per line, framed by opening and closing balance rows
+func (s *DefaultStatementService) WriteCSV(w io.Writer, statement *domain.Statement) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"date", "transaction_id", "type", "description", "debit", "credit", "balance", "currency"},
+		{statement.PeriodStart.Format(statementTimeLayout), "", "", "Opening balance", "", "", statement.OpeningBalance.String(), statement.Currency},
+	}
+	for _, line := range statement.Lines {
+		debit, credit := line.Amount.String(), ""
+		if line.Direction == domain.TransactionDirectionIn {
+			debit, credit = "", line.Amount.String()
+		}
+		rows = append(rows, []string{
+			line.Date.Format(statementTimeLayout),
+			strconv.FormatInt(line.TransactionID, 10),
+			string(line.Type),
+			utils.CSVText(line.Description),
+			debit,
+			credit,
+			line.Balance.String(),
+			line.Amount.Currency,
+		})
+	}
+	rows = append(rows, []string{
+		statement.PeriodEnd.Add(-time.Second).Format(statementTimeLayout), "", "", "Closing balance",
+		statement.TotalOut.String(), statement.TotalIn.String(), statement.ClosingBalance.String(), statement.Currency,
+	})
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
+
+// WritePDF renders a statement as a printable document
+func (s *DefaultStatementService) WritePDF(w io.Writer, statement *domain.Statement) error {
+	period := statement.PeriodStart.Format("January 2006")
+	doc := pdf.New("Statement " + period)
+
+	doc.Bold("ACCOUNT STATEMENT")
+	doc.Line("")
+	doc.Line("Account holder : " + statement.AccountHolder)
+	doc.Line(fmt.Sprintf("Wallet         : %s (#%d)", statement.WalletName, statement.WalletID))
+	doc.Line("Currency       : " + statement.Currency)
+	doc.Line(fmt.Sprintf("Period         : %s - %s", statement.PeriodStart.Format("02 Jan 2006"), statement.PeriodEnd.AddDate(0, 0, -1).Format("02 Jan 2006")))
+	doc.Line("Generated at   : " + statement.GeneratedAt.Format(statementTimeLayout))
+	doc.Line("")
+	doc.Line(fmt.Sprintf("Opening balance : %18s", statement.OpeningBalance.String()))
+	doc.Line(fmt.Sprintf("Total credits   : %18s", statement.TotalIn.String()))
+	doc.Line(fmt.Sprintf("Total debits    : %18s", statement.TotalOut.String()))
+	doc.Bold(fmt.Sprintf("Closing balance : %18s", statement.ClosingBalance.String()))
+	doc.Line("")
+
+	header := statementPDFRow("Date", "Ref", "Description", "Debit", "Credit", "Balance")
+	doc.Bold(header)
+	doc.Line(strings.Repeat("-", len(header)))
+	doc.Line(statementPDFRow(statement.PeriodStart.Format("2006-01-02"), "", "Opening balance", "", "", statement.OpeningBalance.String()))
+	for _, line := range statement.Lines {
+		debit, credit := line.Amount.String(), ""
+		if line.Direction == domain.TransactionDirectionIn {
+			debit, credit = "", line.Amount.String()
+		}
+		doc.Line(statementPDFRow(line.Date.Format("2006-01-02"), strconv.FormatInt(line.TransactionID, 10), line.Description, debit, credit, line.Balance.String()))
+	}
+	doc.Line(strings.Repeat("-", len(header)))
+	doc.Bold(statementPDFRow("", "", "Closing balance", statement.TotalOut.String(), statement.TotalIn.String(), statement.ClosingBalance.String()))
+
+	if _, err := doc.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
+
+// statementPDFRow lays out a table row in fixed-width columns
+func statementPDFRow(date, ref, description, debit, credit, balance string) string {
+	return fmt.Sprintf("%-10s %8s  %-36s %15s %15s %16s", date, ref, column(description, 36), debit, credit, balance)
+}
+
+// column cuts text to width characters, marking the cut with an ellipsis
+func column(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width-3]) + "..."
+}
+
+// describeStatementLine says what a transaction was from the point of view of the wallet
+func describeStatementLine(transaction *domain.Transaction, direction domain.TransactionDirection) string {
+	counterpart := transaction.ReceiverWalletID
+	preposition := "to"
+	if direction == domain.TransactionDirectionIn {
+		counterpart = transaction.SenderWalletID
+		preposition = "from"
+	}
+	wallet := ""
+	if counterpart != nil {
+		wallet = fmt.Sprintf(" %s wallet #%d", preposition, *counterpart)
+	}
+
+	switch transaction.Type {
+	case domain.TransactionTypeTopUp:
+		return "Top-up"
+	case domain.TransactionTypeTransfer:
+		if transaction.CounterAmount != nil {
+			return "Currency transfer" + wallet
+		}
+		return "Transfer" + wallet
+	case domain.TransactionTypeWithdrawal:
+		if transaction.BankCode != nil && transaction.BankAccountNumber != nil {
+			return fmt.Sprintf("Withdrawal to %s %s", *transaction.BankCode, maskAccountNumber(*transaction.BankAccountNumber))
+		}
+		return "Withdrawal"
+	case domain.TransactionTypeFee:
+		if transaction.FeeForID != nil {
+			return fmt.Sprintf("Fee for transaction #%d", *transaction.FeeForID)
+		}
+		return "Fee"
+	case domain.TransactionTypeRefund:
+		if transaction.ReversalOfID != nil {
+			return fmt.Sprintf("Refund of transaction #%d", *transaction.ReversalOfID)
+		}
+		return "Refund"
+	case domain.TransactionTypeCapture:
+		return "Payment" + wallet
+	case domain.TransactionTypePocket:
+		return "Pocket move" + wallet
+	case domain.TransactionTypeAdjustment:
+		return "Balance adjustment"
+	case domain.TransactionTypeExchange:
+		if transaction.ExchangeForID != nil {
+			return fmt.Sprintf("Currency exchange for transaction #%d", *transaction.ExchangeForID)
+		}
+		return "Currency exchange"
+	}
+	return string(transaction.Type) + wallet
+}
+
+// maskAccountNumber keeps the last four digits of a bank account number
+func maskAccountNumber(number string) string {
+	if len(number) <= 4 {
+		return number
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
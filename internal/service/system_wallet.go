@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// systemWallet returns the system wallet of a currency through s.wRepo, see systemWallet
+func (s *DefaultWalletService) systemWallet(ctx context.Context, txDb *goqu.TxDatabase, code domain.SystemWallet, currency string) (*domain.Wallet, error) {
+	return systemWallet(ctx, s.wRepo, txDb, code, currency)
+}
+
+// systemWallet returns the system wallet of a currency, creating it inside txDb the first time
+// the currency is used. The wallet is not locked; lock it with the other wallets of the operation.
+func systemWallet(ctx context.Context, wRepo domain.WalletRepository, txDb *goqu.TxDatabase, code domain.SystemWallet, currency string) (*domain.Wallet, error) {
+	wallet, err := wRepo.GetSystemWallet(ctx, txDb, code, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s wallet: %w", code, err)
+	}
+	if wallet != nil {
+		return wallet, nil
+	}
+
+	now := time.Now()
+	wallet = &domain.Wallet{
+		SystemCode: &code,
+		Currency:   currency,
+		Name:       string(code),
+		Balance:    domain.NewMoney(0, currency),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := wRepo.CreateWithTx(ctx, txDb, wallet); err != nil {
+		return nil, fmt.Errorf("failed to create %s wallet: %w", code, err)
+	}
+	return wallet, nil
+}
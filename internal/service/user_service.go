@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"wallet-api/internal/domain"
@@ -27,6 +29,9 @@ func (s *DefaultUserService) Register(ctx context.Context, user *domain.User) er
 	if existing != nil {
 		return errors.New("email already registered")
 	}
+	if err := s.checkContact(ctx, 0, user); err != nil {
+		return err
+	}
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(user.Password)
@@ -64,3 +69,118 @@ func (s *DefaultUserService) Login(ctx context.Context, email, password string)
 func (s *DefaultUserService) GetProfile(ctx context.Context, id int64) (*domain.User, error) {
 	return s.repo.GetByID(ctx, id)
 }
+
+// UpdateContact sets the phone number and public handle other users can pay the user by
+func (s *DefaultUserService) UpdateContact(ctx context.Context, id int64, phone, handle *string) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrRecipientNotFound
+	}
+	if phone != nil {
+		user.Phone = optional(*phone)
+	}
+	if handle != nil {
+		user.Handle = optional(*handle)
+	}
+	if err := s.checkContact(ctx, id, user); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// ResolveRecipient finds the user a transfer is addressed to. An identifier with an @ inside
+// is an email, one starting with @ a handle, one made of digits a phone number; anything else
+// is tried as a handle.
+func (s *DefaultUserService) ResolveRecipient(ctx context.Context, recipient string) (*domain.User, error) {
+	recipient = strings.TrimSpace(recipient)
+	var (
+		user *domain.User
+		err  error
+	)
+	switch {
+	case recipient == "":
+		return nil, errors.New("recipient is required")
+	case strings.Index(recipient, "@") > 0:
+		user, err = s.repo.GetByEmail(ctx, recipient)
+	case strings.HasPrefix(recipient, "+") || recipient[0] >= '0' && recipient[0] <= '9':
+		phone, normErr := domain.NormalizePhone(recipient)
+		if normErr != nil {
+			return nil, normErr
+		}
+		user, err = s.repo.GetByPhone(ctx, phone)
+	default:
+		handle, normErr := domain.NormalizeHandle(recipient)
+		if normErr != nil {
+			return nil, normErr
+		}
+		user, err = s.repo.GetByHandle(ctx, handle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up recipient: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrRecipientNotFound
+	}
+	return user, nil
+}
+
+// LookupPayee shows a sender who a recipient is without exposing their id or full name
+func (s *DefaultUserService) LookupPayee(ctx context.Context, recipient string) (*domain.Payee, error) {
+	user, err := s.ResolveRecipient(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Payee{
+		DisplayName: domain.MaskName(user.Name),
+		Handle:      user.Handle,
+	}, nil
+}
+
+// checkContact normalizes the phone number and handle of a user and makes sure no other user
+// has them. selfID is the user being updated, 0 on registration.
+func (s *DefaultUserService) checkContact(ctx context.Context, selfID int64, user *domain.User) error {
+	if user.Phone != nil {
+		phone, err := domain.NormalizePhone(*user.Phone)
+		if err != nil {
+			return err
+		}
+		owner, err := s.repo.GetByPhone(ctx, phone)
+		if err != nil {
+			return err
+		}
+		if owner != nil && owner.ID != selfID {
+			return domain.ErrPhoneTaken
+		}
+		user.Phone = &phone
+	}
+	if user.Handle != nil {
+		handle, err := domain.NormalizeHandle(*user.Handle)
+		if err != nil {
+			return err
+		}
+		owner, err := s.repo.GetByHandle(ctx, handle)
+		if err != nil {
+			return err
+		}
+		if owner != nil && owner.ID != selfID {
+			return domain.ErrHandleTaken
+		}
+		user.Handle = &handle
+	}
+	return nil
+}
+
+// optional turns an empty string into nil so a contact field can be cleared
+func optional(value string) *string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return &value
+}
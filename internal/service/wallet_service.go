@@ -7,31 +7,69 @@ import (
 	"fmt"
 	"time"
 	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/database"
 
 	"github.com/doug-martin/goqu/v9"
 )
 
 // DefaultWalletService handles wallet and transaction operations
 type DefaultWalletService struct {
-	db    *sql.DB // raw DB handle to initiate transactions
-	wRepo domain.WalletRepository
-	tRepo domain.TransactionRepository
+	db     *sql.DB // raw DB handle to initiate transactions
+	wRepo  domain.WalletRepository
+	tRepo  domain.TransactionRepository
+	lRepo  domain.LedgerRepository
+	fxRepo domain.FXRepository
+	hRepo  domain.HoldRepository
+
+	limitRepo domain.LimitRepository
+	fees      domain.FeeCalculator
+	payouts   domain.PayoutProvider
 }
 
 // Ensure interface compliance
 var _ domain.TransactionService = &DefaultWalletService{}
 
-func NewWalletService(db *sql.DB, wRepo domain.WalletRepository, tRepo domain.TransactionRepository) domain.TransactionService {
+func NewWalletService(db *sql.DB, wRepo domain.WalletRepository, tRepo domain.TransactionRepository, lRepo domain.LedgerRepository, fxRepo domain.FXRepository, hRepo domain.HoldRepository, limitRepo domain.LimitRepository, fees domain.FeeCalculator, payouts domain.PayoutProvider) domain.TransactionService {
 	return &DefaultWalletService{
-		db:    db,
-		wRepo: wRepo,
-		tRepo: tRepo,
+		db:     db,
+		wRepo:  wRepo,
+		tRepo:  tRepo,
+		lRepo:  lRepo,
+		fxRepo: fxRepo,
+		hRepo:  hRepo,
+
+		limitRepo: limitRepo,
+		fees:      fees,
+		payouts:   payouts,
 	}
 }
 
-func (s *DefaultWalletService) TopUp(ctx context.Context, userID int64, amount float64) (*domain.Wallet, error) {
-	if amount <= 0 {
-		return nil, errors.New("amount must be greater than 0")
+// validateAmount normalizes the currency of amount and makes sure it is positive
+func validateAmount(amount domain.Money) (domain.Money, error) {
+	currency, err := domain.NormalizeCurrency(amount.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	amount.Currency = currency
+	if !amount.IsPositive() {
+		return domain.Money{}, errors.New("amount must be greater than 0")
+	}
+	return amount, nil
+}
+
+// TopUp credits a user wallet from the top-up clearing wallet. Without a walletID the primary
+// wallet of the currency is credited, and created on the first top-up of a currency.
+func (s *DefaultWalletService) TopUp(ctx context.Context, userID, walletID int64, amount domain.Money) (*domain.Wallet, error) {
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := s.feeFor(ctx, domain.FeeOperationTopUp, amount)
+	if err != nil {
+		return nil, err
+	}
+	if fee.IsPositive() && !fee.LessThan(amount) {
+		return nil, domain.ErrFeeExceedsAmount
 	}
 
 	goquDb := goqu.New("mysql", s.db)
@@ -41,49 +79,185 @@ func (s *DefaultWalletService) TopUp(ctx context.Context, userID int64, amount f
 	}
 	defer txDb.Rollback()
 
-	// 1. Try to fetch existing wallet with lock (using repository)
-	wallet, err := s.wRepo.GetWalletForUpdate(ctx, txDb, userID)
+	// 1. Resolve the clearing wallet the money comes from, and the fees wallet when a fee is due
+	clearing, err := s.systemWallet(ctx, txDb, domain.SystemWalletTopUpClearing, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	walletIDs := []int64{clearing.ID}
+	var feeWalletID int64
+	if fee.IsPositive() {
+		feeWallet, err := s.systemWallet(ctx, txDb, domain.SystemWalletFees, amount.Currency)
+		if err != nil {
+			return nil, err
+		}
+		feeWalletID = feeWallet.ID
+		walletIDs = append(walletIDs, feeWallet.ID)
+	}
+	var existing *domain.Wallet
+	if walletID != 0 {
+		if existing, err = s.ownWallet(ctx, userID, walletID, amount.Currency); err != nil {
+			return nil, err
+		}
+	} else if existing, err = s.wRepo.GetByUserIDAndCurrency(ctx, userID, amount.Currency); err != nil {
+		return nil, fmt.Errorf("failed to check wallet: %w", err)
+	}
+	if existing != nil {
+		walletIDs = append(walletIDs, existing.ID)
+	}
+
+	// 2. Lock every wallet in id order
+	wallets, err := s.lockWallets(ctx, txDb, walletIDs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check/lock wallet: %w", err)
+		return nil, err
+	}
+	clearing = wallets[clearing.ID]
+	var wallet *domain.Wallet
+	if existing != nil {
+		wallet = wallets[existing.ID]
+		if err := wallet.CanCredit(); err != nil {
+			return nil, err
+		}
+	} else {
+		// A concurrent first top-up may have created the wallet while we waited
+		wallet, err = s.wRepo.GetWalletForUpdate(ctx, txDb, userID, amount.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check/lock wallet: %w", err)
+		}
 	}
 
 	if wallet == nil {
-		// 2a. Create new wallet if not exists (using repository)
+		// 3a. Create new wallet if not exists (using repository)
+		now := time.Now()
 		wallet = &domain.Wallet{
-			UserID:    userID,
-			Balance:   amount,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			UserID:    &userID,
+			Currency:  amount.Currency,
+			Name:      domain.PrimaryPocketName,
+			IsPrimary: true,
+			Balance:   domain.NewMoney(0, amount.Currency),
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
 		if err := s.wRepo.CreateWithTx(ctx, txDb, wallet); err != nil {
 			return nil, fmt.Errorf("failed to create wallet: %w", err)
 		}
-	} else {
-		// 2b. Update existing wallet (using repository)
-		newBalance := wallet.Balance + amount
-		if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, wallet.ID, newBalance); err != nil {
-			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+
+	// 3b. Move the amount from the clearing wallet into the user wallet
+	newBalance := wallet.Balance.Add(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, wallet.ID, newBalance); err != nil {
+		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	wallet.Balance = newBalance
+	wallet.UpdatedAt = time.Now()
+
+	newClearingBalance := clearing.Balance.Sub(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, clearing.ID, newClearingBalance); err != nil {
+		return nil, fmt.Errorf("failed to update clearing balance: %w", err)
+	}
+	clearing.Balance = newClearingBalance
+
+	// 4. Record the top-up so it shows up in history
+	now := time.Now()
+	transaction := &domain.Transaction{
+		SenderWalletID:   &clearing.ID,
+		ReceiverWalletID: &wallet.ID,
+		Type:             domain.TransactionTypeTopUp,
+		Amount:           amount,
+		Status:           domain.TransactionStatusSuccess,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+
+	// 5. Post the money coming in through the clearing wallet
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(clearing, domain.LedgerDebit, amount),
+		walletPosting(wallet, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	// 6. Take the top-up fee out of the credited amount
+	if fee.IsPositive() {
+		if _, err := s.chargeFee(ctx, txDb, wallet, wallets[feeWalletID], fee, transaction.ID); err != nil {
+			return nil, err
 		}
-		wallet.Balance = newBalance
-		wallet.UpdatedAt = time.Now()
 	}
 
 	if err := txDb.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	wallet.ApplyCurrency()
 	return wallet, nil
 }
 
-func (s *DefaultWalletService) Transfer(ctx context.Context, senderUserID, receiverUserID int64, amount float64) (*domain.Transaction, error) {
+// Transfer moves amount from a wallet of the sender to the primary wallet of the receiver. Without
+// a senderWalletID the primary wallet of the sender is debited.
+func (s *DefaultWalletService) Transfer(ctx context.Context, senderUserID, senderWalletID, receiverUserID int64, amount domain.Money, memo domain.TransferMemo) (*domain.Transaction, error) {
+	return s.commitTransfer(ctx, func(txDb *goqu.TxDatabase) (*domain.Transaction, error) {
+		return s.TransferWithTx(ctx, txDb, senderUserID, senderWalletID, receiverUserID, amount, memo)
+	})
+}
+
+// TransferToWallet is Transfer to a given wallet of another user instead of their primary one
+func (s *DefaultWalletService) TransferToWallet(ctx context.Context, senderUserID, senderWalletID, receiverWalletID int64, amount domain.Money, memo domain.TransferMemo) (*domain.Transaction, error) {
+	return s.commitTransfer(ctx, func(txDb *goqu.TxDatabase) (*domain.Transaction, error) {
+		return s.TransferToWalletWithTx(ctx, txDb, senderUserID, senderWalletID, receiverWalletID, amount, memo)
+	})
+}
+
+// TransferWithTx is Transfer inside a transaction of the caller, so the caller can record what
+// the transfer pays for atomically with it. Nothing is committed; on error the caller must roll
+// tx back.
+func (s *DefaultWalletService) TransferWithTx(ctx context.Context, tx interface{}, senderUserID, senderWalletID, receiverUserID int64, amount domain.Money, memo domain.TransferMemo) (*domain.Transaction, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+
 	// 1. Basic Validation
-	if amount <= 0 {
-		return nil, errors.New("amount must be greater than 0")
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := memo.Normalize(); err != nil {
+		return nil, err
 	}
 	if senderUserID == receiverUserID {
 		return nil, errors.New("cannot transfer to self")
 	}
+	receiverWallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, receiverUserID, amount.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+	}
+	if receiverWallet == nil {
+		return nil, s.missingReceiverWallet(ctx, receiverUserID, amount.Currency)
+	}
+	return s.transfer(ctx, txDb, senderUserID, senderWalletID, receiverWallet.ID, amount, memo)
+}
+
+// TransferToWalletWithTx is TransferToWallet inside a transaction of the caller, see TransferWithTx
+func (s *DefaultWalletService) TransferToWalletWithTx(ctx context.Context, tx interface{}, senderUserID, senderWalletID, receiverWalletID int64, amount domain.Money, memo domain.TransferMemo) (*domain.Transaction, error) {
+	txDb, ok := tx.(*goqu.TxDatabase)
+	if !ok {
+		return nil, errors.New("invalid transaction type")
+	}
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := memo.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.transfer(ctx, txDb, senderUserID, senderWalletID, receiverWalletID, amount, memo)
+}
 
+// commitTransfer runs a transfer in a database transaction of its own
+func (s *DefaultWalletService) commitTransfer(ctx context.Context, transfer func(txDb *goqu.TxDatabase) (*domain.Transaction, error)) (*domain.Transaction, error) {
 	// 2. Begin Database Transaction
 	goquDb := goqu.New("mysql", s.db)
 	txDb, err := goquDb.BeginTx(ctx, nil)
@@ -92,90 +266,331 @@ func (s *DefaultWalletService) Transfer(ctx context.Context, senderUserID, recei
 	}
 	defer txDb.Rollback()
 
-	// 3. Get and lock sender wallet (prevents race condition)
-	senderWallet, err := s.wRepo.GetWalletForUpdate(ctx, txDb, senderUserID)
+	transaction, err := transfer(txDb)
+	if err != nil {
+		return nil, err
+	}
+
+	// 11. Commit transaction
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// transfer moves a validated amount into receiverWalletID and charges the sender the transfer fee
+func (s *DefaultWalletService) transfer(ctx context.Context, txDb *goqu.TxDatabase, senderUserID, senderWalletID, receiverWalletID int64, amount domain.Money, memo domain.TransferMemo) (*domain.Transaction, error) {
+	fee, err := s.feeFor(ctx, domain.FeeOperationTransfer, amount)
 	if err != nil {
+		return nil, err
+	}
+
+	// 3. Resolve both wallets of the currency
+	var senderWallet *domain.Wallet
+	if senderWalletID != 0 {
+		if senderWallet, err = s.ownWallet(ctx, senderUserID, senderWalletID, amount.Currency); err != nil {
+			return nil, err
+		}
+	} else if senderWallet, err = s.wRepo.GetByUserIDAndCurrency(ctx, senderUserID, amount.Currency); err != nil {
 		return nil, fmt.Errorf("failed to fetch sender wallet: %w", err)
 	}
 	if senderWallet == nil {
 		return nil, errors.New("sender wallet not found")
 	}
+	receiverWallet, err := s.wRepo.GetByID(ctx, receiverWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+	}
+	if receiverWallet == nil || receiverWallet.UserID == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	if *receiverWallet.UserID == senderUserID {
+		return nil, errors.New("cannot transfer to self")
+	}
+	if receiverWallet.Currency != amount.Currency {
+		return nil, &domain.CurrencyMismatchError{SourceCurrency: amount.Currency, TargetCurrency: receiverWallet.Currency}
+	}
 
-	// 4. Check Balance
-	if senderWallet.Balance < amount {
-		return nil, errors.New("insufficient balance")
+	walletIDs := []int64{senderWallet.ID, receiverWallet.ID}
+	if !senderWallet.IsPrimary {
+		// Limits are checked under the lock of the primary wallet, take it in id order too
+		primary, err := s.wRepo.GetByUserIDAndCurrency(ctx, senderUserID, amount.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sender wallet: %w", err)
+		}
+		if primary != nil {
+			walletIDs = append(walletIDs, primary.ID)
+		}
+	}
+	var feeWalletID int64
+	if fee.IsPositive() {
+		feeWallet, err := s.systemWallet(ctx, txDb, domain.SystemWalletFees, amount.Currency)
+		if err != nil {
+			return nil, err
+		}
+		feeWalletID = feeWallet.ID
+		walletIDs = append(walletIDs, feeWallet.ID)
 	}
 
-	// 5. Get and lock receiver wallet (prevents race condition)
-	receiverWallet, err := s.wRepo.GetWalletForUpdate(ctx, txDb, receiverUserID)
+	// 4. Lock every wallet in id order (prevents race conditions and deadlocks with reversals)
+	wallets, err := s.lockWallets(ctx, txDb, walletIDs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch receiver wallet: %w", err)
+		return nil, err
 	}
-	if receiverWallet == nil {
-		return nil, errors.New("receiver wallet not found")
+	senderWallet, receiverWallet = wallets[senderWallet.ID], wallets[receiverWallet.ID]
+
+	// 5. Check both wallets accept the movement and the balance covers the amount and its fee,
+	// funds reserved by holds cannot be spent
+	if err := checkMovement(senderWallet, receiverWallet); err != nil {
+		return nil, err
+	}
+	if senderWallet.Available().LessThan(amount.Add(fee)) {
+		return nil, domain.ErrInsufficientBalance
+	}
+	if err := s.checkLimits(ctx, txDb, senderWallet, amount, amount, 1); err != nil {
+		return nil, err
+	}
+	// References are unique per user; the lock on their primary wallet, taken for the limits,
+	// keeps two transfers from different pockets from claiming the same one
+	if memo.ClientReference != "" {
+		existing, err := s.tRepo.GetBySenderReferenceWithTx(ctx, txDb, senderUserID, memo.ClientReference)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check client reference: %w", err)
+		}
+		if existing != nil {
+			return nil, domain.ErrDuplicateReference
+		}
 	}
 
 	// 6. Update sender balance (deduct)
-	newSenderBalance := senderWallet.Balance - amount
+	newSenderBalance := senderWallet.Balance.Sub(amount)
 	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, senderWallet.ID, newSenderBalance); err != nil {
 		return nil, fmt.Errorf("failed to update sender balance: %w", err)
 	}
+	senderWallet.Balance = newSenderBalance
 
 	// 7. Update receiver balance (add)
-	newReceiverBalance := receiverWallet.Balance + amount
+	newReceiverBalance := receiverWallet.Balance.Add(amount)
 	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, receiverWallet.ID, newReceiverBalance); err != nil {
 		return nil, fmt.Errorf("failed to update receiver balance: %w", err)
 	}
+	receiverWallet.Balance = newReceiverBalance
 
 	// 8. Create transaction record
 	now := time.Now()
 	transaction := &domain.Transaction{
 		SenderWalletID:   &senderWallet.ID,
 		ReceiverWalletID: &receiverWallet.ID,
+		Type:             domain.TransactionTypeTransfer,
 		Amount:           amount,
 		Status:           domain.TransactionStatusSuccess,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
+	memo.ApplyTo(transaction)
+	if transaction.ClientReference != nil {
+		transaction.ReferenceUserID = &senderUserID
+	}
 
 	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
 		return nil, fmt.Errorf("failed to create transaction record: %w", err)
 	}
 
-	// 9. Commit transaction
-	if err := txDb.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	// 9. Post the balanced ledger entries for the transfer
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(senderWallet, domain.LedgerDebit, amount),
+		walletPosting(receiverWallet, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	// 10. Charge the sender the transfer fee
+	if fee.IsPositive() {
+		if _, err := s.chargeFee(ctx, txDb, senderWallet, wallets[feeWalletID], fee, transaction.ID); err != nil {
+			return nil, err
+		}
 	}
+	transaction.Fee = &fee
 
 	return transaction, nil
 }
 
-func (s *DefaultWalletService) GetHistory(ctx context.Context, userID int64, page, limit int) ([]domain.Transaction, error) {
-	// Simple implementation delegated to repo
-	// We need to resolve UserID to WalletID first, outside of transaction is fine for read-only history
-	wallet, err := s.wRepo.GetByUserID(ctx, userID)
+// missingReceiverWallet explains why a receiver has no wallet in currency
+func (s *DefaultWalletService) missingReceiverWallet(ctx context.Context, receiverUserID int64, currency string) error {
+	wallets, err := s.wRepo.GetByUserID(ctx, receiverUserID)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to fetch receiver wallet: %w", err)
 	}
-	if wallet == nil {
-		return nil, errors.New("wallet not found")
+	if len(wallets) == 0 {
+		return errors.New("receiver wallet not found")
+	}
+	return &domain.CurrencyMismatchError{SourceCurrency: currency, TargetCurrency: wallets[0].Currency}
+}
+
+// GetHistory lists a page of the user's transactions that match filter, newest first, with the
+// paging metadata. A short page already tells the total, so rows are only counted when the page
+// is full or lies past the end.
+func (s *DefaultWalletService) GetHistory(ctx context.Context, userID int64, filter domain.TransactionFilter, page, limit int) ([]domain.Transaction, *database.Meta, error) {
+	walletIDs, err := s.historyWallets(ctx, userID, &filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page < 1 {
+		page = 1
 	}
 
 	offset := (page - 1) * limit
-	return s.tRepo.GetByWalletID(ctx, wallet.ID, limit, offset)
+	transactions, err := s.tRepo.GetByWalletIDs(ctx, walletIDs, filter, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range transactions {
+		transactions[i].Direction = transactions[i].DirectionFor(walletIDs...)
+	}
+
+	total := uint64(offset + len(transactions))
+	if len(transactions) == limit || (len(transactions) == 0 && offset > 0) {
+		if total, err = s.tRepo.CountByWalletIDs(ctx, walletIDs, filter); err != nil {
+			return nil, nil, err
+		}
+	}
+	if transactions == nil {
+		transactions = []domain.Transaction{}
+	}
+	return transactions, database.NewMeta(total, uint64(page), uint64(limit)), nil
 }
 
-func (s *DefaultWalletService) GetBalance(ctx context.Context, userID int64) (*domain.Wallet, error) {
-	wallet, err := s.wRepo.GetByUserID(ctx, userID)
+// GetHistoryPage reads a page of the user's transactions by cursor, newest first. One row past
+// limit is read to tell whether the page in the reading direction is the last.
+func (s *DefaultWalletService) GetHistoryPage(ctx context.Context, userID int64, filter domain.TransactionFilter, cursor string, limit int) (*domain.TransactionPage, error) {
+	var position *domain.HistoryCursor
+	if cursor != "" {
+		var err error
+		if position, err = domain.ParseHistoryCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	walletIDs, err := s.historyWallets(ctx, userID, &filter)
 	if err != nil {
 		return nil, err
 	}
-	if wallet == nil {
+
+	transactions, err := s.tRepo.GetByWalletIDsAt(ctx, walletIDs, filter, position, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	backward := position != nil && position.Backward
+	more := len(transactions) > limit
+	if more {
+		if backward {
+			transactions = transactions[1:] // The extra row is the newest
+		} else {
+			transactions = transactions[:limit]
+		}
+	}
+	for i := range transactions {
+		transactions[i].Direction = transactions[i].DirectionFor(walletIDs...)
+	}
+
+	page := &domain.TransactionPage{Transactions: transactions}
+	if page.Transactions == nil {
+		page.Transactions = []domain.Transaction{}
+	}
+	if len(transactions) == 0 {
+		// Past either end: offer the way back to where the client came from
+		if position != nil {
+			reverse := domain.HistoryCursor{CreatedAt: position.CreatedAt, ID: position.ID, Backward: !position.Backward}
+			if position.Backward {
+				page.NextCursor = reverse.Encode()
+			} else {
+				page.PrevCursor = reverse.Encode()
+			}
+		}
+		return page, nil
+	}
+	if more || backward {
+		page.NextCursor = domain.CursorAfter(&transactions[len(transactions)-1]).Encode()
+	}
+	if (more && backward) || (position != nil && !backward) {
+		page.PrevCursor = domain.CursorBefore(&transactions[0]).Encode()
+	}
+	return page, nil
+}
+
+// ExportHistory returns every transaction of the user that matches filter as a stream. Invalid
+// filters and missing wallets are reported here, before the caller starts writing a response.
+func (s *DefaultWalletService) ExportHistory(ctx context.Context, userID int64, filter domain.TransactionFilter) (domain.TransactionStream, error) {
+	walletIDs, err := s.historyWallets(ctx, userID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(fn func(domain.Transaction) error) error {
+		return s.tRepo.StreamByWalletIDs(ctx, walletIDs, filter, func(transaction domain.Transaction) error {
+			transaction.Direction = transaction.DirectionFor(walletIDs...)
+			return fn(transaction)
+		})
+	}, nil
+}
+
+// historyWallets validates filter and resolves the wallets of the user it covers
+func (s *DefaultWalletService) historyWallets(ctx context.Context, userID int64, filter *domain.TransactionFilter) ([]int64, error) {
+	if filter.Currency != "" {
+		currency, err := domain.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = currency
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Resolving wallets outside of a transaction is fine for read-only history
+	wallets, err := s.wRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	walletIDs := make([]int64, 0, len(wallets))
+	for _, wallet := range wallets {
+		if filter.Currency == "" || wallet.Currency == filter.Currency {
+			walletIDs = append(walletIDs, wallet.ID)
+		}
+	}
+	if len(walletIDs) == 0 {
+		return nil, domain.ErrWalletNotFound
+	}
+	return walletIDs, nil
+}
+
+// GetBalance lists the open pockets of a user, primary wallets first, or only walletID when it is set
+func (s *DefaultWalletService) GetBalance(ctx context.Context, userID, walletID int64) ([]domain.Wallet, error) {
+	if walletID != 0 {
+		wallet, err := s.ownWallet(ctx, userID, walletID, "")
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Wallet{*wallet}, nil
+	}
+
+	all, err := s.wRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]domain.Wallet, 0, len(all))
+	for _, wallet := range all {
+		if wallet.ArchivedAt == nil {
+			wallets = append(wallets, wallet)
+		}
+	}
+	if len(wallets) == 0 {
 		// Return default wallet with 0 balance
-		return &domain.Wallet{
-			UserID:  userID,
-			Balance: 0,
-		}, nil
+		return []domain.Wallet{{
+			UserID:   &userID,
+			Currency: domain.DefaultCurrency,
+			Balance:  domain.NewMoney(0, domain.DefaultCurrency),
+		}}, nil
 	}
-	return wallet, nil
+	return wallets, nil
 }
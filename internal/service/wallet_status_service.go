@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// DefaultWalletStatusFeedLimit caps a page of the status event feed
+const DefaultWalletStatusFeedLimit = 100
+
+// DefaultWalletStatusService freezes, unfreezes and closes wallets and keeps their history
+type DefaultWalletStatusService struct {
+	db        *sql.DB
+	wRepo     domain.WalletRepository
+	eventRepo domain.WalletStatusEventRepository
+}
+
+// Ensure interface compliance
+var _ domain.WalletStatusService = &DefaultWalletStatusService{}
+
+func NewWalletStatusService(db *sql.DB, wRepo domain.WalletRepository, eventRepo domain.WalletStatusEventRepository) domain.WalletStatusService {
+	return &DefaultWalletStatusService{
+		db:        db,
+		wRepo:     wRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+// SetStatus moves a wallet to status and records who did it and why. Closing is final and only
+// allowed once the wallet is empty; system wallets always stay active.
+func (s *DefaultWalletStatusService) SetStatus(ctx context.Context, walletID int64, status domain.WalletStatus, reason, actor string) (*domain.Wallet, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidWalletStatus, status)
+	}
+	reason, actor = strings.TrimSpace(reason), strings.TrimSpace(actor)
+	if reason == "" || actor == "" {
+		return nil, errors.New("reason and actor are required")
+	}
+
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	// 1. Lock the wallet so no transfer sees it half way through the change
+	wallet, err := s.wRepo.GetByIDForUpdate(ctx, txDb, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	// 2. Check the transition
+	switch {
+	case wallet.SystemCode != nil:
+		return nil, fmt.Errorf("%w: system wallets cannot change status", domain.ErrInvalidWalletStatus)
+	case wallet.Status == domain.WalletStatusClosed:
+		return nil, domain.ErrWalletClosed
+	case wallet.Status == status:
+		return nil, fmt.Errorf("%w: wallet is already %s", domain.ErrInvalidWalletStatus, status)
+	case status == domain.WalletStatusClosed && (!wallet.Balance.IsZero() || !wallet.HeldBalance.IsZero()):
+		return nil, domain.ErrWalletNotEmpty
+	}
+
+	// 3. Apply it and keep the history
+	now := time.Now()
+	event := &domain.WalletStatusEvent{
+		WalletID:   wallet.ID,
+		FromStatus: wallet.Status,
+		ToStatus:   status,
+		Reason:     reason,
+		Actor:      actor,
+		CreatedAt:  now,
+	}
+	wallet.Status = status
+	wallet.StatusReason = &reason
+	wallet.StatusChangedBy = &actor
+	wallet.StatusChangedAt = &now
+	if err := s.wRepo.UpdateStatusWithTx(ctx, txDb, wallet); err != nil {
+		return nil, fmt.Errorf("failed to update wallet status: %w", err)
+	}
+	if err := s.eventRepo.CreateWithTx(ctx, txDb, event); err != nil {
+		return nil, fmt.Errorf("failed to record status event: %w", err)
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return wallet, nil
+}
+
+func (s *DefaultWalletStatusService) Events(ctx context.Context, walletID int64) ([]domain.WalletStatusEvent, error) {
+	wallet, err := s.wRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	return s.eventRepo.GetByWalletID(ctx, walletID)
+}
+
+// Feed returns the status changes after afterID so ops tooling can poll for new ones
+func (s *DefaultWalletStatusService) Feed(ctx context.Context, afterID int64, limit int) ([]domain.WalletStatusEvent, error) {
+	if limit <= 0 || limit > DefaultWalletStatusFeedLimit {
+		limit = DefaultWalletStatusFeedLimit
+	}
+	return s.eventRepo.ListSince(ctx, afterID, limit)
+}
+
+// checkMovement makes sure money may leave from and enter to. Both wallets must be locked so
+// a concurrent freeze is seen.
+func checkMovement(from, to *domain.Wallet) error {
+	if err := from.CanDebit(); err != nil {
+		return fmt.Errorf("sender %w", err)
+	}
+	if err := to.CanCredit(); err != nil {
+		return fmt.Errorf("receiver %w", err)
+	}
+	return nil
+}
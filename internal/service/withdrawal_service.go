@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"wallet-api/internal/domain"
+	"wallet-api/internal/pkg/utils"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// Withdraw holds the funds in a pending withdrawal and hands it to the payout provider.
+// The provider outcome settles or fails the withdrawal; an asynchronous provider, or a payout
+// call with an unknown outcome, leaves it pending until SettleWithdrawal or FailWithdrawal is
+// called through the admin API.
+func (s *DefaultWalletService) Withdraw(ctx context.Context, userID int64, amount domain.Money, account domain.BankAccount) (*domain.Transaction, error) {
+	amount, err := validateAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	account.BankCode = strings.TrimSpace(account.BankCode)
+	account.AccountNumber = strings.TrimSpace(account.AccountNumber)
+	account.AccountName = strings.TrimSpace(account.AccountName)
+	if account.BankCode == "" || account.AccountNumber == "" || account.AccountName == "" {
+		return nil, errors.New("bank code, account number and account name are required")
+	}
+
+	transaction, err := s.holdWithdrawal(ctx, userID, amount, account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.payouts.Payout(ctx, domain.PayoutRequest{
+		TransactionID: transaction.ID,
+		Amount:        amount,
+		Account:       account,
+	})
+	if err != nil {
+		// The outcome is unknown, keep the funds held until an operator settles or fails it
+		utils.LogErrorf("payout for transaction %d failed, leaving it pending: %v", transaction.ID, err)
+		return transaction, nil
+	}
+
+	switch result.Status {
+	case domain.TransactionStatusSuccess:
+		return s.SettleWithdrawal(ctx, transaction.ID, result.Reference)
+	case domain.TransactionStatusFailed:
+		return s.FailWithdrawal(ctx, transaction.ID, result.FailureReason)
+	default:
+		return transaction, nil
+	}
+}
+
+// holdWithdrawal moves the amount from the wallet into the treasury wallet and records the
+// pending transaction. The treasury keeps the funds whether the payout succeeds or not.
+func (s *DefaultWalletService) holdWithdrawal(ctx context.Context, userID int64, amount domain.Money, account domain.BankAccount) (*domain.Transaction, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	wallet, err := s.wRepo.GetByUserIDAndCurrency(ctx, userID, amount.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, domain.ErrWalletNotFound
+	}
+	treasury, err := s.systemWallet(ctx, txDb, domain.SystemWalletTreasury, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := s.lockWallets(ctx, txDb, wallet.ID, treasury.ID)
+	if err != nil {
+		return nil, err
+	}
+	wallet, treasury = wallets[wallet.ID], wallets[treasury.ID]
+	if err := wallet.CanDebit(); err != nil {
+		return nil, err
+	}
+	if wallet.Available().LessThan(amount) {
+		return nil, domain.ErrInsufficientBalance
+	}
+	if err := s.checkLimits(ctx, txDb, wallet, amount, amount, 1); err != nil {
+		return nil, err
+	}
+
+	newBalance := wallet.Balance.Sub(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, wallet.ID, newBalance); err != nil {
+		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	wallet.Balance = newBalance
+
+	newTreasuryBalance := treasury.Balance.Add(amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, treasury.ID, newTreasuryBalance); err != nil {
+		return nil, fmt.Errorf("failed to update treasury balance: %w", err)
+	}
+	treasury.Balance = newTreasuryBalance
+
+	now := time.Now()
+	transaction := &domain.Transaction{
+		SenderWalletID:    &wallet.ID,
+		ReceiverWalletID:  &treasury.ID,
+		Type:              domain.TransactionTypeWithdrawal,
+		Amount:            amount,
+		Status:            domain.TransactionStatusPending,
+		BankCode:          &account.BankCode,
+		BankAccountNumber: &account.AccountNumber,
+		BankAccountName:   &account.AccountName,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.tRepo.CreateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		walletPosting(wallet, domain.LedgerDebit, amount),
+		walletPosting(treasury, domain.LedgerCredit, amount),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// SettleWithdrawal marks a pending withdrawal as paid out. The funds already sit in the treasury
+// wallet, so no balance moves.
+func (s *DefaultWalletService) SettleWithdrawal(ctx context.Context, transactionID int64, reference string) (*domain.Transaction, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	transaction, err := s.pendingWithdrawalForUpdate(ctx, txDb, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Status = domain.TransactionStatusSuccess
+	if reference != "" {
+		transaction.ExternalReference = &reference
+	}
+	transaction.UpdatedAt = time.Now()
+	if err := s.tRepo.UpdateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	// Withdrawals held before system wallets existed still sit in the withdrawal hold account
+	if transaction.ReceiverWalletID == nil {
+		if err := s.postJournal(ctx, txDb, &transaction.ID,
+			externalPosting(domain.LedgerAccountWithdrawalHold, domain.LedgerDebit, transaction.Amount),
+			externalPosting(domain.LedgerAccountPayout, domain.LedgerCredit, transaction.Amount),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// FailWithdrawal marks a pending withdrawal as failed and returns the funds from the treasury
+// wallet in the same database transaction
+func (s *DefaultWalletService) FailWithdrawal(ctx context.Context, transactionID int64, reason string) (*domain.Transaction, error) {
+	goquDb := goqu.New("mysql", s.db)
+	txDb, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txDb.Rollback()
+
+	transaction, err := s.pendingWithdrawalForUpdate(ctx, txDb, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	walletIDs := []int64{*transaction.SenderWalletID}
+	if transaction.ReceiverWalletID != nil {
+		walletIDs = append(walletIDs, *transaction.ReceiverWalletID)
+	}
+	wallets, err := s.lockWallets(ctx, txDb, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	wallet := wallets[*transaction.SenderWalletID]
+
+	newBalance := wallet.Balance.Add(transaction.Amount)
+	if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, wallet.ID, newBalance); err != nil {
+		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	wallet.Balance = newBalance
+
+	// Withdrawals held before system wallets existed are returned from the withdrawal hold account
+	source := externalPosting(domain.LedgerAccountWithdrawalHold, domain.LedgerDebit, transaction.Amount)
+	if transaction.ReceiverWalletID != nil {
+		treasury := wallets[*transaction.ReceiverWalletID]
+		newTreasuryBalance := treasury.Balance.Sub(transaction.Amount)
+		if err := s.wRepo.UpdateBalanceWithTx(ctx, txDb, treasury.ID, newTreasuryBalance); err != nil {
+			return nil, fmt.Errorf("failed to update treasury balance: %w", err)
+		}
+		treasury.Balance = newTreasuryBalance
+		source = walletPosting(treasury, domain.LedgerDebit, transaction.Amount)
+	}
+
+	if reason == "" {
+		reason = "payout failed"
+	}
+	transaction.Status = domain.TransactionStatusFailed
+	transaction.FailureReason = &reason
+	transaction.UpdatedAt = time.Now()
+	if err := s.tRepo.UpdateWithTx(ctx, txDb, transaction); err != nil {
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	if err := s.postJournal(ctx, txDb, &transaction.ID,
+		source,
+		walletPosting(wallet, domain.LedgerCredit, transaction.Amount),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := txDb.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// pendingWithdrawalForUpdate locks a withdrawal row and makes sure it can still be settled
+func (s *DefaultWalletService) pendingWithdrawalForUpdate(ctx context.Context, txDb *goqu.TxDatabase, transactionID int64) (*domain.Transaction, error) {
+	transaction, err := s.tRepo.GetByIDForUpdate(ctx, txDb, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	if transaction == nil || transaction.Type != domain.TransactionTypeWithdrawal || transaction.SenderWalletID == nil {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if transaction.Status != domain.TransactionStatusPending {
+		return nil, domain.ErrTransactionNotPending
+	}
+	return transaction, nil
+}
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    journal_id CHAR(32) NOT NULL,
+    transaction_id BIGINT NULL,
+    wallet_id BIGINT NULL,
+    account VARCHAR(64) NOT NULL,
+    direction ENUM('debit', 'credit') NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    balance_after DECIMAL(15, 2) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE SET NULL,
+    INDEX idx_ledger_entries_journal (journal_id),
+    INDEX idx_ledger_entries_transaction (transaction_id),
+    INDEX idx_ledger_entries_wallet (wallet_id),
+    INDEX idx_ledger_entries_account (account)
+);
+
+-- Existing balances predate the ledger, post them as opening balances so every wallet can be proven
+INSERT INTO ledger_entries (journal_id, wallet_id, account, direction, amount, balance_after)
+SELECT MD5(CONCAT('opening:', id)), NULL, 'external:opening_balance', 'debit', balance, NULL
+FROM wallets WHERE balance <> 0;
+
+INSERT INTO ledger_entries (journal_id, wallet_id, account, direction, amount, balance_after)
+SELECT MD5(CONCAT('opening:', id)), id, CONCAT('wallet:', id), 'credit', balance, balance
+FROM wallets WHERE balance <> 0;
@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    DROP INDEX idx_transactions_type,
+    DROP COLUMN type;
@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    ADD COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund') NOT NULL DEFAULT 'transfer' AFTER receiver_wallet_id,
+    ADD INDEX idx_transactions_type (type);
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    DROP COLUMN failure_reason,
+    DROP COLUMN external_reference,
+    DROP COLUMN bank_account_name,
+    DROP COLUMN bank_account_number,
+    DROP COLUMN bank_code;
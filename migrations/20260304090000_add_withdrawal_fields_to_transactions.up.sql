@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    ADD COLUMN bank_code VARCHAR(32) NULL AFTER status,
+    ADD COLUMN bank_account_number VARCHAR(64) NULL AFTER bank_code,
+    ADD COLUMN bank_account_name VARCHAR(255) NULL AFTER bank_account_number,
+    ADD COLUMN external_reference VARCHAR(128) NULL AFTER bank_account_name,
+    ADD COLUMN failure_reason VARCHAR(255) NULL AFTER external_reference;
@@ -0,0 +1,10 @@
+ALTER TABLE ledger_entries
+    DROP COLUMN currency;
+
+ALTER TABLE transactions
+    DROP COLUMN currency;
+
+ALTER TABLE wallets
+    DROP INDEX uq_wallets_user_currency,
+    ADD UNIQUE KEY uq_wallets_user_id (user_id),
+    DROP COLUMN currency;
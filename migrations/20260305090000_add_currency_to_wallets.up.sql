@@ -0,0 +1,10 @@
+ALTER TABLE wallets
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER user_id,
+    DROP INDEX uq_wallets_user_id,
+    ADD UNIQUE KEY uq_wallets_user_currency (user_id, currency);
+
+ALTER TABLE transactions
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
+
+ALTER TABLE ledger_entries
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
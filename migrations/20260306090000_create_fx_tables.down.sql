@@ -0,0 +1,8 @@
+ALTER TABLE transactions
+    DROP COLUMN fx_spread,
+    DROP COLUMN fx_rate,
+    DROP COLUMN counter_currency,
+    DROP COLUMN counter_amount;
+
+DROP TABLE IF EXISTS fx_quotes;
+DROP TABLE IF EXISTS fx_rates;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(20, 8) NOT NULL,
+    spread_bps INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_fx_rates_pair (base_currency, quote_currency)
+);
+
+CREATE TABLE IF NOT EXISTS fx_quotes (
+    id CHAR(32) NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    source_currency CHAR(3) NOT NULL,
+    target_currency CHAR(3) NOT NULL,
+    source_amount DECIMAL(15, 2) NOT NULL,
+    target_amount DECIMAL(15, 2) NOT NULL,
+    mid_rate DECIMAL(20, 8) NOT NULL,
+    rate DECIMAL(20, 8) NOT NULL,
+    spread_bps INT NOT NULL DEFAULT 0,
+    spread DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    transaction_id BIGINT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    INDEX idx_fx_quotes_user (user_id)
+);
+
+ALTER TABLE transactions
+    ADD COLUMN counter_amount DECIMAL(15, 2) NULL AFTER currency,
+    ADD COLUMN counter_currency CHAR(3) NULL AFTER counter_amount,
+    ADD COLUMN fx_rate DECIMAL(20, 8) NULL AFTER counter_currency,
+    ADD COLUMN fx_spread DECIMAL(15, 2) NULL AFTER fx_rate;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    DROP FOREIGN KEY fk_transactions_reversal_of,
+    DROP INDEX idx_transactions_reversal_of,
+    DROP COLUMN reversal_reason,
+    DROP COLUMN reversal_of_id;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    ADD COLUMN reversal_of_id BIGINT NULL AFTER fx_spread,
+    ADD COLUMN reversal_reason VARCHAR(255) NULL AFTER reversal_of_id,
+    ADD CONSTRAINT fk_transactions_reversal_of FOREIGN KEY (reversal_of_id) REFERENCES transactions(id),
+    ADD INDEX idx_transactions_reversal_of (reversal_of_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS holds;
+
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund') NOT NULL DEFAULT 'transfer';
+
+ALTER TABLE wallets
+    DROP COLUMN held_balance;
@@ -0,0 +1,23 @@
+ALTER TABLE wallets
+    ADD COLUMN held_balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00 AFTER balance;
+
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund', 'capture') NOT NULL DEFAULT 'transfer';
+
+CREATE TABLE IF NOT EXISTS holds (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    wallet_id BIGINT NOT NULL,
+    merchant_user_id BIGINT NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    captured_amount DECIMAL(15, 2) NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    status ENUM('active', 'captured', 'voided', 'expired') NOT NULL DEFAULT 'active',
+    transaction_id BIGINT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE CASCADE,
+    FOREIGN KEY (merchant_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    INDEX idx_holds_status_expires (status, expires_at)
+);
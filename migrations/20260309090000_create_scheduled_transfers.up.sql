@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    receiver_user_id BIGINT NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    frequency ENUM('once', 'daily', 'weekly', 'monthly') NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    occurrences INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(255) NULL,
+    status ENUM('active', 'paused', 'completed', 'cancelled') NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (receiver_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_scheduled_transfers_user (user_id),
+    INDEX idx_scheduled_transfers_due (status, next_run_at)
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_transfer_executions (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    schedule_id BIGINT NOT NULL,
+    occurrence_at TIMESTAMP NOT NULL,
+    attempt INT NOT NULL DEFAULT 1,
+    status ENUM('pending', 'success', 'failed') NOT NULL,
+    transaction_id BIGINT NULL,
+    error VARCHAR(255) NULL,
+    executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (schedule_id) REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    INDEX idx_schedule_executions_schedule (schedule_id, executed_at)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    DROP INDEX idx_transactions_batch_id,
+    DROP COLUMN batch_id;
@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    ADD COLUMN batch_id CHAR(32) NULL AFTER reversal_reason,
+    ADD INDEX idx_transactions_batch_id (batch_id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    requester_user_id BIGINT NOT NULL,
+    payer_user_id BIGINT NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    status ENUM('pending', 'accepted', 'declined', 'cancelled', 'expired') NOT NULL DEFAULT 'pending',
+    transaction_id BIGINT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (requester_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (payer_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    INDEX idx_payment_requests_payer (payer_user_id, status),
+    INDEX idx_payment_requests_requester (requester_user_id, status)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    DROP INDEX idx_transactions_sender_created;
+DROP TABLE IF EXISTS transfer_limits;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS transfer_limits (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL DEFAULT 0, -- 0 holds the global limits
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    per_transaction_max DECIMAL(15, 2) NULL,
+    daily_amount_max DECIMAL(15, 2) NULL,
+    daily_count_max INT NULL,
+    monthly_amount_max DECIMAL(15, 2) NULL,
+    monthly_count_max INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_transfer_limits_user_currency (user_id, currency)
+);
+
+ALTER TABLE transactions
+    ADD INDEX idx_transactions_sender_created (sender_wallet_id, created_at);
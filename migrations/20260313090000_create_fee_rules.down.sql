@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP FOREIGN KEY fk_transactions_fee_for,
+    DROP COLUMN fee_for_id;
+DROP TABLE IF EXISTS fee_rules;
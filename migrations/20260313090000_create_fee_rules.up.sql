@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    operation ENUM('transfer', 'topup') NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    min_amount DECIMAL(15, 2) NOT NULL DEFAULT 0, -- Lower bound of the tier
+    flat_fee DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    percent_bps INT NOT NULL DEFAULT 0,
+    min_fee DECIMAL(15, 2) NULL,
+    max_fee DECIMAL(15, 2) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_fee_rules_tier (operation, currency, min_amount)
+);
+
+ALTER TABLE transactions
+    ADD COLUMN fee_for_id BIGINT NULL AFTER batch_id,
+    ADD CONSTRAINT fk_transactions_fee_for FOREIGN KEY (fee_for_id) REFERENCES transactions(id);
@@ -0,0 +1,15 @@
+ALTER TABLE transactions
+    DROP FOREIGN KEY fk_transactions_exchange_for,
+    DROP COLUMN exchange_for_id;
+
+DELETE FROM transactions WHERE type = 'exchange';
+
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund', 'capture') NOT NULL DEFAULT 'transfer';
+
+DELETE FROM wallets WHERE system_code IS NOT NULL;
+
+ALTER TABLE wallets
+    DROP INDEX uq_wallets_system_currency,
+    DROP COLUMN system_code,
+    MODIFY COLUMN user_id BIGINT NOT NULL;
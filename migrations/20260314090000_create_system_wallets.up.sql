@@ -0,0 +1,18 @@
+ALTER TABLE wallets
+    MODIFY COLUMN user_id BIGINT NULL,
+    ADD COLUMN system_code VARCHAR(32) NULL AFTER user_id,
+    ADD UNIQUE KEY uq_wallets_system_currency (system_code, currency);
+
+-- Wallets of other currencies are created on first use
+INSERT INTO wallets (system_code, currency, balance) VALUES
+    ('treasury', 'IDR', 0.00),
+    ('topup_clearing', 'IDR', 0.00),
+    ('fees', 'IDR', 0.00),
+    ('suspense', 'IDR', 0.00);
+
+-- Cross-currency transfers settle through an FX position system wallet per currency; the
+-- exchange row records what the positions swapped so replays move them too
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund', 'capture', 'exchange') NOT NULL DEFAULT 'transfer',
+    ADD COLUMN exchange_for_id BIGINT NULL AFTER fee_for_id,
+    ADD CONSTRAINT fk_transactions_exchange_for FOREIGN KEY (exchange_for_id) REFERENCES transactions(id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS wallet_status_events;
+
+ALTER TABLE wallets
+    DROP COLUMN status_changed_at,
+    DROP COLUMN status_changed_by,
+    DROP COLUMN status_reason,
+    DROP COLUMN status;
@@ -0,0 +1,17 @@
+ALTER TABLE wallets
+    ADD COLUMN status ENUM('active', 'debit_frozen', 'frozen', 'closed') NOT NULL DEFAULT 'active' AFTER held_balance,
+    ADD COLUMN status_reason VARCHAR(255) NULL AFTER status,
+    ADD COLUMN status_changed_by VARCHAR(100) NULL AFTER status_reason,
+    ADD COLUMN status_changed_at TIMESTAMP NULL AFTER status_changed_by;
+
+CREATE TABLE IF NOT EXISTS wallet_status_events (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    wallet_id BIGINT NOT NULL,
+    from_status ENUM('active', 'debit_frozen', 'frozen', 'closed') NOT NULL,
+    to_status ENUM('active', 'debit_frozen', 'frozen', 'closed') NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE CASCADE,
+    INDEX idx_wallet_status_events_wallet (wallet_id, id)
+);
@@ -0,0 +1,12 @@
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund', 'capture', 'exchange') NOT NULL DEFAULT 'transfer';
+
+ALTER TABLE wallets
+    DROP INDEX uq_wallets_primary,
+    DROP COLUMN primary_user_id,
+    ADD UNIQUE KEY uq_wallets_user_currency (user_id, currency);
+ALTER TABLE wallets
+    DROP INDEX idx_wallets_user_currency,
+    DROP COLUMN archived_at,
+    DROP COLUMN is_primary,
+    DROP COLUMN name;
@@ -0,0 +1,19 @@
+ALTER TABLE wallets
+    ADD COLUMN name VARCHAR(50) NOT NULL DEFAULT 'Main' AFTER currency,
+    ADD COLUMN is_primary BOOLEAN NOT NULL DEFAULT FALSE AFTER name,
+    ADD COLUMN archived_at TIMESTAMP NULL AFTER is_primary;
+
+UPDATE wallets SET is_primary = TRUE WHERE user_id IS NOT NULL;
+UPDATE wallets SET name = system_code WHERE system_code IS NOT NULL;
+
+-- A user may own several pockets per currency but only one primary wallet; primary_user_id is
+-- NULL for every other pocket, and NULLs never collide in a unique key
+ALTER TABLE wallets
+    ADD INDEX idx_wallets_user_currency (user_id, currency);
+ALTER TABLE wallets
+    DROP INDEX uq_wallets_user_currency,
+    ADD COLUMN primary_user_id BIGINT AS (IF(is_primary, user_id, NULL)) STORED,
+    ADD UNIQUE KEY uq_wallets_primary (primary_user_id, currency);
+
+ALTER TABLE transactions
+    MODIFY COLUMN type ENUM('topup', 'transfer', 'withdrawal', 'fee', 'adjustment', 'refund', 'capture', 'pocket', 'exchange') NOT NULL DEFAULT 'transfer';
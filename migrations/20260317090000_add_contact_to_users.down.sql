@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP INDEX uq_users_handle,
+    DROP INDEX uq_users_phone,
+    DROP COLUMN handle,
+    DROP COLUMN phone;
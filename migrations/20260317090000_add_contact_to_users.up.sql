@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN phone VARCHAR(16) NULL AFTER email,
+    ADD COLUMN handle VARCHAR(30) NULL AFTER phone,
+    ADD UNIQUE KEY uq_users_phone (phone),
+    ADD UNIQUE KEY uq_users_handle (handle);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS qr_codes (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    wallet_id BIGINT NOT NULL,
+    type ENUM('static', 'dynamic') NOT NULL,
+    token CHAR(32) NOT NULL,
+    amount DECIMAL(15, 2) NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    merchant_name VARCHAR(25) NOT NULL,
+    merchant_city VARCHAR(15) NOT NULL,
+    reference VARCHAR(25) NULL,
+    payload VARCHAR(512) NOT NULL,
+    status ENUM('active', 'paid', 'expired') NOT NULL DEFAULT 'active',
+    transaction_id BIGINT NULL,
+    expires_at TIMESTAMP NULL,
+    paid_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    UNIQUE KEY uq_qr_codes_token (token),
+    INDEX idx_qr_codes_wallet (wallet_id, created_at)
+);
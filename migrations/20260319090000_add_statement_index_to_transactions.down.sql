@@ -0,0 +1,2 @@
+ALTER TABLE transactions
+    DROP INDEX idx_transactions_receiver_created;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions
+    ADD INDEX idx_transactions_receiver_created (receiver_wallet_id, created_at);
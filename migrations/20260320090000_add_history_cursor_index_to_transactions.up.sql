@@ -0,0 +1,5 @@
+-- Keyset pages order by (created_at, id). InnoDB appends the primary key to secondary indexes,
+-- so the per-wallet (wallet, created_at) indexes already end in id; this one serves histories
+-- spanning many wallets, read in order until the page is full.
+ALTER TABLE transactions
+    ADD INDEX idx_transactions_created_id (created_at, id);
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    DROP INDEX uq_transactions_user_client_reference,
+    DROP COLUMN metadata,
+    DROP COLUMN reference_user_id,
+    DROP COLUMN client_reference,
+    DROP COLUMN description;
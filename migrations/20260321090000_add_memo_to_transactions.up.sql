@@ -0,0 +1,8 @@
+-- reference_user_id is the sending user, so a client reference is unique per user across all
+-- of their pockets
+ALTER TABLE transactions
+    ADD COLUMN description VARCHAR(255) NULL AFTER failure_reason,
+    ADD COLUMN client_reference VARCHAR(64) NULL AFTER description,
+    ADD COLUMN reference_user_id BIGINT NULL AFTER client_reference,
+    ADD COLUMN metadata JSON NULL AFTER reference_user_id,
+    ADD UNIQUE KEY uq_transactions_user_client_reference (reference_user_id, client_reference);